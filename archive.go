@@ -0,0 +1,53 @@
+package main
+
+import (
+	"archive/zip"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+)
+
+// collectArchiveCandidates checks the Go files of the -archive zip file, if it's set.
+//
+// Files are read without the extraction, they get synthetic names
+// that are the archive path joined with the file path inside of it,
+// like "/tmp/src.zip/foo/bar.go".
+// See collectSourceCandidates for the limitations.
+func (ctxt *context) collectArchiveCandidates() error {
+	if ctxt.flags.archive == "" {
+		return nil
+	}
+	abs, err := filepath.Abs(ctxt.flags.archive)
+	if err != nil {
+		return err
+	}
+	r, err := zip.OpenReader(abs)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	ctxt.infoPrintf("check %q archive", ctxt.flags.archive)
+	sources := make(map[string][]byte)
+	for _, zf := range r.File {
+		if zf.FileInfo().IsDir() || !strings.HasSuffix(zf.Name, ".go") {
+			continue
+		}
+		data, err := readZipFile(zf)
+		if err != nil {
+			return fmt.Errorf("%s: %v", zf.Name, err)
+		}
+		sources[filepath.Join(abs, filepath.FromSlash(zf.Name))] = data
+	}
+	return ctxt.collectSourceCandidates(sources)
+}
+
+func readZipFile(zf *zip.File) ([]byte, error) {
+	rc, err := zf.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return ioutil.ReadAll(rc)
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"archive/zip"
+	"fmt"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestArchive(t *testing.T) {
+	files := []struct {
+		name string
+		data string
+	}{
+		{"foo/a.go", "package foo\n\nfunc emptyMaps() {\n\t_ = make(map[int]int)\n\t_ = make(map[int]int)\n\t_ = map[int]int{}\n}\n"},
+		{"foo/a_test.go", "package foo_test\n\nimport \"testing\"\n\nfunc TestA(t *testing.T) {}\n"},
+		{"bar/b.go", "package bar\n\nfunc hexLits() {\n\t_ = 0xff\n\t_ = 0xfe\n\t_ = 0xFD\n}\n"},
+		{"README.md", "not a Go file\n"},
+		// Imports are not resolved, but the rest of the package is checked.
+		{"baz/c.go", "package baz\n\nimport \"example.com/other\"\n\nfunc rangeList() {\n\tfor i := range other.List() {\n\t\t_ = i\n\t}\n\t_ = map[int]int{}\n}\n"},
+	}
+	dir, err := ioutil.TempDir("", "go-consistent")
+	if err != nil {
+		t.Fatalf("create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	archive := filepath.Join(dir, "src.zip")
+	out, err := os.Create(archive)
+	if err != nil {
+		t.Fatalf("create archive: %v", err)
+	}
+	w := zip.NewWriter(out)
+	for _, f := range files {
+		fw, err := w.Create(f.name)
+		if err != nil {
+			t.Fatalf("add %s: %v", f.name, err)
+		}
+		if _, err := fw.Write([]byte(f.data)); err != nil {
+			t.Fatalf("write %s: %v", f.name, err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("close archive: %v", err)
+	}
+	if err := out.Close(); err != nil {
+		t.Fatalf("close archive: %v", err)
+	}
+
+	ctxt := newTestContext(t)
+	ctxt.flags.archive = archive
+	ctxt.flags.pedantic = true
+	runAnalysis(t, ctxt)
+
+	var warnings []string
+	visitWarings(ctxt, func(pos token.Position, v, suggested *opVariant) {
+		rel, err := filepath.Rel(archive, pos.Filename)
+		if err != nil {
+			t.Fatalf("unexpected file name: %s", pos.Filename)
+		}
+		warnings = append(warnings, fmt.Sprintf("%s:%d:%d: %s", filepath.ToSlash(rel), pos.Line, pos.Column, v.op.name))
+	})
+	want := []string{
+		"bar/b.go:6:6: hex lit",
+		"baz/c.go:9:6: empty map",
+		"foo/a.go:6:6: empty map",
+	}
+	if !reflect.DeepEqual(warnings, want) {
+		t.Errorf("warnings mismatch:\nhave: %v\nwant: %v", warnings, want)
+	}
+	if ctxt.files != 4 {
+		t.Errorf("checked files: have %d, want 4", ctxt.files)
+	}
+}
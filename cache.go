@@ -0,0 +1,98 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+)
+
+// inferredCache is a -inferred-cache file contents.
+//
+// It persists the inferred suggestions between the runs, so checking
+// a subset of files reports the warnings in accordance with the
+// conventions inferred by the previous runs.
+type inferredCache struct {
+	// Version is a tool version that produced the cache, see toolVersion.
+	// Cache produced by another version is discarded.
+	Version string `json:"version"`
+
+	// Suggestions maps operation names to the inference scope keys
+	// and their suggested variant letters, like in -force flag.
+	Suggestions map[string]map[string]string `json:"suggestions"`
+}
+
+// loadInferredCache reads the -inferred-cache file, if it's set.
+// Missing file is not an error, it's created by the saveInferredCache.
+func (ctxt *context) loadInferredCache() error {
+	if ctxt.flags.inferredCache == "" {
+		return nil
+	}
+	ctxt.inferredCache = &inferredCache{
+		Version:     toolVersion(),
+		Suggestions: make(map[string]map[string]string),
+	}
+	data, err := ioutil.ReadFile(ctxt.flags.inferredCache)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var cache inferredCache
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return fmt.Errorf("%s: %v", ctxt.flags.inferredCache, err)
+	}
+	if cache.Version != ctxt.inferredCache.Version {
+		ctxt.infoPrintf("discard inferred cache: produced by %s version", cache.Version)
+		return nil
+	}
+	if cache.Suggestions != nil {
+		ctxt.inferredCache.Suggestions = cache.Suggestions
+	}
+	return nil
+}
+
+// saveInferredCache writes the -inferred-cache file, if it's set.
+func (ctxt *context) saveInferredCache() error {
+	if ctxt.inferredCache == nil {
+		return nil
+	}
+	data, err := json.MarshalIndent(ctxt.inferredCache, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(ctxt.flags.inferredCache, append(data, '\n'), 0644)
+}
+
+// cachedSuggestion returns the op variant suggested for the scope
+// by the inferred cache.
+// Returns nil if there is no valid cached suggestion.
+func (ctxt *context) cachedSuggestion(op *operation, scopeKey string) *opVariant {
+	if ctxt.inferredCache == nil {
+		return nil
+	}
+	letter := ctxt.inferredCache.Suggestions[op.name][scopeKey]
+	if len(letter) != 1 || letter[0] < 'A' || int(letter[0]-'A') >= len(op.variants) {
+		return nil
+	}
+	return op.variants[letter[0]-'A']
+}
+
+// cacheSuggestion records the op variant suggested for the scope
+// in the inferred cache.
+func (ctxt *context) cacheSuggestion(op *operation, scopeKey string, v *opVariant) {
+	if ctxt.inferredCache == nil {
+		return
+	}
+	scopes := ctxt.inferredCache.Suggestions[op.name]
+	if scopes == nil {
+		scopes = make(map[string]string)
+		ctxt.inferredCache.Suggestions[op.name] = scopes
+	}
+	for i, variant := range op.variants {
+		if variant == v {
+			scopes[scopeKey] = variantLetter(i)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+)
+
+func runCachedTest(t *testing.T, cacheFile string, paths ...string) int {
+	ctxt := newTestContext(t)
+	ctxt.paths = paths
+	ctxt.flags.inferredCache = cacheFile
+	steps := []func() error{
+		ctxt.initCheckers,
+		ctxt.collectAllCandidates,
+		ctxt.loadInferredCache,
+		ctxt.assignSuggestions,
+		ctxt.saveInferredCache,
+		ctxt.printWarnings,
+	}
+	for _, step := range steps {
+		if err := step(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	return ctxt.warnings
+}
+
+func TestInferredCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "go-consistent")
+	if err != nil {
+		t.Fatalf("create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	cacheFile := filepath.Join(dir, "cache.json")
+	subset := path.Join("testdata", "filter", "b.go")
+
+	// Without the cache, make(map) is the only variant in the subset.
+	if n := runCachedTest(t, "", subset); n != 0 {
+		t.Fatalf("expected no warnings without cache, got %d", n)
+	}
+
+	// Map literals are inferred from the entire package.
+	if n := runCachedTest(t, cacheFile, "./"+path.Join("testdata", "filter")); n != 1 {
+		t.Fatalf("expected 1 warning for the package, got %d", n)
+	}
+	if n := runCachedTest(t, cacheFile, subset); n != 1 {
+		t.Errorf("expected cached suggestion to be used for the subset, got %d warnings", n)
+	}
+
+	// Cache produced by another tool version is discarded.
+	data := `{"version": "v0.0.0-old", "suggestions": {"empty map": {"": "B"}}}`
+	if err := ioutil.WriteFile(cacheFile, []byte(data), 0644); err != nil {
+		t.Fatalf("write cache: %v", err)
+	}
+	if n := runCachedTest(t, cacheFile, subset); n != 0 {
+		t.Errorf("expected outdated cache to be discarded, got %d warnings", n)
+	}
+}
@@ -1,11 +1,19 @@
 package main
 
 import (
+	"bytes"
 	"go/ast"
+	"go/constant"
+	"go/printer"
 	"go/token"
 	"go/types"
+	goversion "go/version"
 	"regexp"
+	"strconv"
 	"strings"
+	"text/template"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/go-toolsmith/astcast"
 	"github.com/go-toolsmith/astequal"
@@ -13,74 +21,3929 @@ import (
 )
 
 func (ctxt *context) mark(n ast.Node, v *opVariant) {
+	ctxt.addCandidate(n, v, ctxt.opScopeID(v.op))
+}
+
+// markScoped is like mark, but the candidate is only compared to other
+// candidates of the current inference scope that were marked with the same key.
+func (ctxt *context) markScoped(n ast.Node, v *opVariant, key string) {
+	scopeKey := ctxt.scopeKeyOf(ctxt.opScopeID(v.op))
+	ctxt.addCandidate(n, v, ctxt.scopeIDOf(scopeKey+"\x00"+key))
+}
+
+func (ctxt *context) addCandidate(n ast.Node, v *opVariant, scopeID int) {
 	v.count++
 	pos := ctxt.fset.Position(n.Pos())
+	if ctxt.flags.debugAST {
+		ctxt.printMatchedNode(pos, n, v)
+	}
 	ctxt.candidates = append(ctxt.candidates, candidate{
 		variantID:  v.id,
 		locationID: ctxt.locs.Insert(pos.Filename, pos.Line, pos.Column),
+		scopeID:    scopeID,
+	})
+}
+
+// printMatchedNode prints the node matched by the checker, see -debug-ast.
+// Multi-line nodes are printed as a single line.
+func (ctxt *context) printMatchedNode(pos token.Position, n ast.Node, v *opVariant) {
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, ctxt.fset, n); err != nil {
+		ctxt.logger.Printf("\tdebug ast: %s: %v", pos, err)
+		return
+	}
+	letter := "?"
+	for i, variant := range v.op.variants {
+		if variant == v {
+			letter = variantLetter(i)
+		}
+	}
+	src := strings.Join(strings.Fields(buf.String()), " ")
+	ctxt.logger.Printf("\tdebug ast: %s: %s=%s: %T: %s", pos, v.op.name, letter, n, src)
+}
+
+// opScopeID returns the inference scope ID of the package being checked for op.
+func (ctxt *context) opScopeID(op *operation) int {
+	if op.scope == "" {
+		return ctxt.scopeID
+	}
+	return ctxt.opScopeIDs[op.scope]
+}
+
+type operation struct {
+	// name is a human-readable operation descriptor.
+	//
+	// Initialized by checker constructor.
+	name string
+
+	// doc is a one-line operation description, see -rules-doc.
+	//
+	// Initialized by checker constructor.
+	doc string
+
+	// forced is an op variant that is suggested regardless of the usage counts.
+	//
+	// Initialized by checker constructor for operations with a fixed preference.
+	// Can be overwritten by context.initCheckers from the -force flag.
+	forced *opVariant
+
+	// preferred is an op variant that is suggested whenever it's used at least once.
+	// Otherwise, the suggestion is inferred as usual.
+	//
+	// Initialized by checker constructor for operations where
+	// one variant is safer than the others.
+	preferred *opVariant
+
+	// variants is a list of equivalent operation forms.
+	//
+	// Initialized by checker constructor.
+	variants []*opVariant
+
+	// pedantic marks operations that are only checked in -pedantic mode.
+	//
+	// Initialized by checker constructor.
+	pedantic bool
+
+	// scope is an inference scope override for this operation.
+	// Empty scope means that the -scope flag value is used.
+	//
+	// Initialized by checker constructor for operations with a default override.
+	// Can be overwritten by context.initCheckers from the config.
+	scope string
+
+	// message is a warnings text template, see warningText.
+	// Nil means that the default text is used.
+	//
+	// Initialized by context.initCheckers from the config.
+	message *template.Template
+}
+
+// suggest returns the op variant that should be suggested given
+// the variants usage counts: the forced variant, if any, the preferred
+// variant if it's used, or the most frequently used one otherwise.
+//
+// undecided reports whether the suggestion is ambiguous, that is
+// op is not forced and its most frequently used variants have equal counts.
+func (op *operation) suggest(count func(v *opVariant) int) (suggested *opVariant, undecided bool) {
+	if op.forced != nil {
+		return op.forced, false
+	}
+	if op.preferred != nil && count(op.preferred) != 0 {
+		return op.preferred, false
+	}
+	suggested = op.variants[0]
+	ties := 0
+	for _, v := range op.variants[1:] {
+		switch n := count(v); {
+		case n > count(suggested):
+			suggested, ties = v, 0
+		case n == count(suggested):
+			ties++
+		}
+	}
+	return suggested, ties != 0 && count(suggested) != 0
+}
+
+type opVariant struct {
+	// id is an globally-unique operation variant ID.
+	//
+	// Initialized by context.initCheckers.
+	id int
+
+	// op is a reference to a containing operation.
+	//
+	// Initialized by context.initCheckers.
+	op *operation
+
+	// warning is a message to use if this variant is not used
+	// when it is the suggested one.
+	//
+	// For example, if the variant requires the usage of the
+	// parenthesis, warning should suggest adding them.
+	//
+	// Initialized by checker constructor.
+	warning string
+
+	// count is a counter for op variant usages.
+	//
+	// Updated during the context.collectCandidates.
+	count int
+}
+
+type checker interface {
+	Visit(n ast.Node) bool
+	Operation() *operation
+}
+
+type checkerBase struct {
+	ctxt *context
+	op   *operation
+}
+
+func (c *checkerBase) Operation() *operation {
+	return c.op
+}
+
+type candidate struct {
+	variantID  int
+	locationID int
+
+	// scopeID is an inference scope ID, see context.scopeID.
+	scopeID int
+}
+
+// scopedOp is an operation inside a particular inference scope.
+type scopedOp struct {
+	scopeID int
+	op      *operation
+}
+
+type sliceConcatChecker struct {
+	checkerBase
+
+	appendCall opVariant
+	copyCall   opVariant
+}
+
+func newSliceConcatChecker(ctxt *context) checker {
+	c := &sliceConcatChecker{}
+	c.ctxt = ctxt
+	c.appendCall.warning = "use `dst = append(dst, src...)`"
+	c.copyCall.warning = "use `copy(dst[len(x):], src)`"
+	c.op = &operation{
+		name:     "slice concat",
+		doc:      "append vs copy for slice concatenation",
+		variants: []*opVariant{&c.appendCall, &c.copyCall},
+		pedantic: true,
+	}
+	return c
+}
+
+func (c *sliceConcatChecker) Visit(n ast.Node) bool {
+	switch n := n.(type) {
+	case *ast.AssignStmt:
+		// Only `dst = append(dst, src...)` is recognized.
+		// Other spread appends are usually not a concatenation.
+		if len(n.Lhs) != 1 || len(n.Rhs) != 1 {
+			return true
+		}
+		call := astcast.ToCallExpr(n.Rhs[0])
+		if astcast.ToIdent(call.Fun).Name != "append" || call.Ellipsis == token.NoPos {
+			return true
+		}
+		if len(call.Args) != 2 || !astequal.Expr(n.Lhs[0], call.Args[0]) {
+			return true
+		}
+		c.ctxt.mark(n, &c.appendCall)
+	case *ast.ExprStmt:
+		// Only `copy(dst[len(x):], src)` is recognized, since copying
+		// into the tail of a preallocated slice is the most common way
+		// to concatenate slices with copy.
+		call := astcast.ToCallExpr(n.X)
+		if astcast.ToIdent(call.Fun).Name != "copy" || len(call.Args) != 2 {
+			return true
+		}
+		dst := astcast.ToSliceExpr(call.Args[0])
+		if dst.High != nil || astcast.ToIdent(astcast.ToCallExpr(dst.Low).Fun).Name != "len" {
+			return true
+		}
+		c.ctxt.mark(n, &c.copyCall)
+	}
+	return true
+}
+
+type typeAssertChecker struct {
+	checkerBase
+
+	commaOk   opVariant
+	unchecked opVariant
+}
+
+func newTypeAssertChecker(ctxt *context) checker {
+	c := &typeAssertChecker{}
+	c.ctxt = ctxt
+	c.commaOk.warning = "use comma-ok form, like in `v, ok := x.(T)`"
+	c.unchecked.warning = "use single-value form, like in `v := x.(T)`"
+	c.op = &operation{
+		name: "type assert",
+		doc:  "comma-ok vs single-value type assertions",
+		// commaOk goes first, so it wins if both forms are used equally often.
+		variants: []*opVariant{&c.commaOk, &c.unchecked},
+	}
+	return c
+}
+
+func (c *typeAssertChecker) Visit(n ast.Node) bool {
+	lhsLen, rhs := assignParts(n)
+	if len(rhs) != 1 {
+		return true
+	}
+	// Type is nil for `x.(type)` inside type switches; skip them.
+	e, ok := rhs[0].(*ast.TypeAssertExpr)
+	if !ok || e.Type == nil {
+		return true
+	}
+	switch lhsLen {
+	case 1:
+		c.ctxt.mark(n, &c.unchecked)
+	case 2:
+		c.ctxt.mark(n, &c.commaOk)
+	}
+	return true
+}
+
+type mapIndexChecker struct {
+	checkerBase
+
+	commaOk   opVariant
+	unchecked opVariant
+}
+
+func newMapIndexChecker(ctxt *context) checker {
+	c := &mapIndexChecker{}
+	c.ctxt = ctxt
+	c.commaOk.warning = "use comma-ok form, like in `v, ok := m[k]`"
+	c.unchecked.warning = "use single-value form, like in `v := m[k]`"
+	c.op = &operation{
+		name:     "map index",
+		doc:      "comma-ok vs single-value map indexing",
+		variants: []*opVariant{&c.commaOk, &c.unchecked},
+		pedantic: true,
+	}
+	return c
+}
+
+func (c *mapIndexChecker) Visit(n ast.Node) bool {
+	lhsLen, rhs := assignParts(n)
+	if len(rhs) != 1 {
+		return true
+	}
+	e, ok := rhs[0].(*ast.IndexExpr)
+	if !ok {
+		return true
+	}
+	if _, ok := underlyingOf(c.ctxt.info, e.X).(*types.Map); !ok {
+		return true
+	}
+	switch lhsLen {
+	case 1:
+		c.ctxt.mark(n, &c.unchecked)
+	case 2:
+		c.ctxt.mark(n, &c.commaOk)
+	}
+	return true
+}
+
+type typedNilReturnChecker struct {
+	checkerBase
+
+	untypedNil opVariant
+	typedNil   opVariant
+}
+
+func newTypedNilReturnChecker(ctxt *context) checker {
+	c := &typedNilReturnChecker{}
+	c.ctxt = ctxt
+	c.untypedNil.warning = "return untyped nil instead of a nil pointer variable"
+	c.typedNil.warning = "return a nil pointer variable instead of untyped nil"
+	c.op = &operation{
+		name:     "typed nil return",
+		doc:      "untyped nil vs nil pointer variables in returns",
+		variants: []*opVariant{&c.untypedNil, &c.typedNil},
+		pedantic: true,
+	}
+	// Returning typed nil as an interface is almost always a bug.
+	c.op.forced = &c.untypedNil
+	return c
+}
+
+func (c *typedNilReturnChecker) Visit(n ast.Node) bool {
+	var typ *ast.FuncType
+	var body *ast.BlockStmt
+	switch n := n.(type) {
+	case *ast.FuncDecl:
+		typ, body = n.Type, n.Body
+	case *ast.FuncLit:
+		typ, body = n.Type, n.Body
+	default:
+		return true
+	}
+	if body == nil || typ.Results == nil || typ.Results.NumFields() != 1 {
+		return true
+	}
+	result := c.ctxt.info.TypeOf(typ.Results.List[0].Type)
+	if result == nil || !types.IsInterface(result) {
+		return true
+	}
+
+	nilVars := c.nilPtrVars(body)
+	inspectFuncBody(body, func(n ast.Node) {
+		ret, ok := n.(*ast.ReturnStmt)
+		if !ok || len(ret.Results) != 1 {
+			return
+		}
+		id, ok := ret.Results[0].(*ast.Ident)
+		if !ok {
+			return
+		}
+		obj := c.ctxt.info.ObjectOf(id)
+		if _, ok := obj.(*types.Nil); ok {
+			c.ctxt.mark(ret, &c.untypedNil)
+		} else if nilVars[obj] {
+			c.ctxt.mark(ret, &c.typedNil)
+		}
+	})
+	return true
+}
+
+// nilPtrVars collects pointer vars that are declared as nil and
+// are never assigned or addressed inside the body.
+//
+// Only `var x *T` and `var x *T = nil` declarations are recognized;
+// such vars are guaranteed to be nil, so returning them
+// as an interface value always produces a non-nil typed nil.
+func (c *typedNilReturnChecker) nilPtrVars(body *ast.BlockStmt) map[types.Object]bool {
+	vars := make(map[types.Object]bool)
+	inspectFuncBody(body, func(n ast.Node) {
+		spec, ok := n.(*ast.ValueSpec)
+		if !ok || spec.Type == nil {
+			return
+		}
+		if len(spec.Values) != 0 && !(len(spec.Values) == 1 && valueOf(spec.Values[0]) == "nil") {
+			return
+		}
+		for _, name := range spec.Names {
+			obj := c.ctxt.info.ObjectOf(name)
+			if obj == nil {
+				continue
+			}
+			if _, ok := obj.Type().Underlying().(*types.Pointer); ok {
+				vars[obj] = true
+			}
+		}
+	})
+	inspectFuncBody(body, func(n ast.Node) {
+		var targets []ast.Expr
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			targets = n.Lhs
+		case *ast.UnaryExpr:
+			if n.Op == token.AND {
+				targets = []ast.Expr{n.X}
+			}
+		case *ast.IncDecStmt:
+			targets = []ast.Expr{n.X}
+		}
+		for _, x := range targets {
+			if id, ok := x.(*ast.Ident); ok {
+				delete(vars, c.ctxt.info.ObjectOf(id))
+			}
+		}
+	})
+	return vars
+}
+
+type scalarPtrAllocChecker struct {
+	checkerBase
+
+	newCall      opVariant
+	addressOfVar opVariant
+}
+
+func newScalarPtrAllocChecker(ctxt *context) checker {
+	c := &scalarPtrAllocChecker{}
+	c.ctxt = ctxt
+	c.newCall.warning = "use new(T) for scalar *T allocation"
+	c.addressOfVar.warning = "use `x := v; p := &x` for scalar *T allocation"
+	c.op = &operation{
+		name:     "scalar ptr alloc",
+		doc:      "new(T) vs address of a variable for scalar pointers",
+		variants: []*opVariant{&c.newCall, &c.addressOfVar},
+	}
+	return c
+}
+
+func (c *scalarPtrAllocChecker) Visit(n ast.Node) bool {
+	if call, ok := n.(*ast.CallExpr); ok {
+		if len(call.Args) != 1 || !isBuiltin(c.ctxt.info, call.Fun, "new") {
+			return true
+		}
+		if _, ok := c.ctxt.info.TypeOf(call.Args[0]).(*types.Basic); ok {
+			c.ctxt.mark(n, &c.newCall)
+		}
+		return true
+	}
+
+	// Look for `x := v` immediately followed by `p := &x` (or `p = &x`).
+	// Only the zero v values are matched, since new(T) can't express the others.
+	list := stmtList(n)
+	for i := 1; i < len(list); i++ {
+		decl, ok := list[i-1].(*ast.AssignStmt)
+		if !ok || decl.Tok != token.DEFINE || len(decl.Lhs) != 1 || len(decl.Rhs) != 1 {
+			continue
+		}
+		x, ok := decl.Lhs[0].(*ast.Ident)
+		if !ok {
+			continue
+		}
+		if _, ok := c.ctxt.info.TypeOf(x).(*types.Basic); !ok || !c.isZeroValue(decl.Rhs[0]) {
+			continue
+		}
+		assign, ok := list[i].(*ast.AssignStmt)
+		if !ok || len(assign.Lhs) != 1 || len(assign.Rhs) != 1 {
+			continue
+		}
+		addr := astcast.ToUnaryExpr(assign.Rhs[0])
+		if addr.Op == token.AND && astcast.ToIdent(addr.X).Name == x.Name {
+			c.ctxt.mark(decl, &c.addressOfVar)
+		}
+	}
+	return true
+}
+
+// isZeroValue reports whether x is a zero value constant, like 0, "" or false.
+func (c *scalarPtrAllocChecker) isZeroValue(x ast.Expr) bool {
+	v := c.ctxt.info.Types[x].Value
+	if v == nil {
+		return false
+	}
+	switch v.Kind() {
+	case constant.Bool:
+		return !constant.BoolVal(v)
+	case constant.String:
+		return constant.StringVal(v) == ""
+	case constant.Int, constant.Float, constant.Complex:
+		return constant.Sign(v) == 0
+	default:
+		return false
+	}
+}
+
+type ifInitChecker struct {
+	checkerBase
+
+	withInit opVariant
+	preStmt  opVariant
+}
+
+func newIfInitChecker(ctxt *context) checker {
+	c := &ifInitChecker{}
+	c.ctxt = ctxt
+	c.withInit.warning = "move the declaration into if init, like in `if x := f(); x != nil {}`"
+	c.preStmt.warning = "move the declaration out of if init, like in `x := f(); if x != nil {}`"
+	c.op = &operation{
+		name:     "if init",
+		doc:      "if statement init vs a separate declaration",
+		variants: []*opVariant{&c.withInit, &c.preStmt},
+		pedantic: true,
+	}
+	return c
+}
+
+func (c *ifInitChecker) Visit(n ast.Node) bool {
+	if stmt, ok := n.(*ast.IfStmt); ok {
+		init, ok := stmt.Init.(*ast.AssignStmt)
+		if ok && init.Tok == token.DEFINE {
+			c.ctxt.mark(n, &c.withInit)
+		}
+		return true
+	}
+
+	// Look for `x := f()` immediately followed by `if` that uses x,
+	// given that x is not used after that if statement.
+	// Only then the declaration can be moved into the if init.
+	list := stmtList(n)
+	for i := 0; i+1 < len(list); i++ {
+		decl, ok := list[i].(*ast.AssignStmt)
+		if !ok || decl.Tok != token.DEFINE {
+			continue
+		}
+		stmt, ok := list[i+1].(*ast.IfStmt)
+		if !ok || stmt.Init != nil {
+			continue
+		}
+		if c.isMovable(decl, stmt.Cond, list[i+2:]) {
+			c.ctxt.mark(decl, &c.preStmt)
+		}
+	}
+	return true
+}
+
+func (c *ifInitChecker) isMovable(decl *ast.AssignStmt, cond ast.Expr, rest []ast.Stmt) bool {
+	usedInCond := false
+	for _, lhs := range decl.Lhs {
+		obj := c.ctxt.info.ObjectOf(astcast.ToIdent(lhs))
+		if obj == nil {
+			continue
+		}
+		if refersTo(c.ctxt.info, cond, obj) {
+			usedInCond = true
+		}
+		for _, stmt := range rest {
+			if refersTo(c.ctxt.info, stmt, obj) {
+				return false
+			}
+		}
+	}
+	return usedInCond
+}
+
+type sliceCloneChecker struct {
+	checkerBase
+
+	appendCall opVariant
+	copyCall   opVariant
+}
+
+func newSliceCloneChecker(ctxt *context) checker {
+	c := &sliceCloneChecker{}
+	c.ctxt = ctxt
+	c.appendCall.warning = "use `dst := append([]T(nil), src...)`"
+	c.copyCall.warning = "use `dst := make([]T, len(src)); copy(dst, src)`"
+	c.op = &operation{
+		name:     "slice clone",
+		doc:      "append vs make and copy for slice cloning",
+		variants: []*opVariant{&c.appendCall, &c.copyCall},
+		pedantic: true,
+	}
+	return c
+}
+
+func (c *sliceCloneChecker) Visit(n ast.Node) bool {
+	if assign, ok := n.(*ast.AssignStmt); ok {
+		if len(assign.Rhs) == 1 && c.isAppendClone(assign.Rhs[0]) {
+			c.ctxt.mark(n, &c.appendCall)
+		}
+		return true
+	}
+
+	// Look for `dst := make([]T, len(src))` immediately followed by `copy(dst, src)`.
+	list := stmtList(n)
+	for i := 0; i+1 < len(list); i++ {
+		assign, ok := list[i].(*ast.AssignStmt)
+		if !ok || len(assign.Lhs) != 1 || len(assign.Rhs) != 1 {
+			continue
+		}
+		mk := astcast.ToCallExpr(assign.Rhs[0])
+		if astcast.ToIdent(mk.Fun).Name != "make" || len(mk.Args) != 2 {
+			continue
+		}
+		length := astcast.ToCallExpr(mk.Args[1])
+		if astcast.ToIdent(length.Fun).Name != "len" || len(length.Args) != 1 {
+			continue
+		}
+		cp := astcast.ToCallExpr(astcast.ToExprStmt(list[i+1]).X)
+		if astcast.ToIdent(cp.Fun).Name != "copy" || len(cp.Args) != 2 {
+			continue
+		}
+		if astequal.Expr(cp.Args[0], assign.Lhs[0]) && astequal.Expr(cp.Args[1], length.Args[0]) {
+			c.ctxt.mark(assign, &c.copyCall)
+		}
+	}
+	return true
+}
+
+// isAppendClone reports whether x is `append(empty, src...)`,
+// where empty is either `[]T(nil)` or `[]T{}`.
+func (c *sliceCloneChecker) isAppendClone(x ast.Expr) bool {
+	call := astcast.ToCallExpr(x)
+	if astcast.ToIdent(call.Fun).Name != "append" || call.Ellipsis == token.NoPos || len(call.Args) != 2 {
+		return false
+	}
+	switch dst := call.Args[0].(type) {
+	case *ast.CallExpr:
+		return len(dst.Args) == 1 && valueOf(dst.Args[0]) == "nil"
+	case *ast.CompositeLit:
+		return len(dst.Elts) == 0
+	default:
+		return false
+	}
+}
+
+type durationLitChecker struct {
+	checkerBase
+
+	untypedConst opVariant
+	conversion   opVariant
+}
+
+func newDurationLitChecker(ctxt *context) checker {
+	c := &durationLitChecker{}
+	c.ctxt = ctxt
+	c.untypedConst.warning = "use untyped constant, like in `5 * time.Second`"
+	c.conversion.warning = "use conversion, like in `time.Duration(5) * time.Second`"
+	c.op = &operation{
+		name:     "duration lit",
+		doc:      "untyped constants vs conversions in duration expressions",
+		variants: []*opVariant{&c.untypedConst, &c.conversion},
+	}
+	return c
+}
+
+func (c *durationLitChecker) Visit(n ast.Node) bool {
+	e := astcast.ToBinaryExpr(n)
+	if e.Op != token.MUL {
+		return true
+	}
+	x := e.X
+	if !c.isUnit(e.Y) {
+		if !c.isUnit(e.X) {
+			return true
+		}
+		x = e.Y
+	}
+
+	// Non-constant operands require a conversion, so there is no choice.
+	switch x := x.(type) {
+	case *ast.BasicLit:
+		c.ctxt.mark(n, &c.untypedConst)
+	case *ast.CallExpr:
+		pkgPath, name := qualifiedIdent(c.ctxt.info, x.Fun)
+		if pkgPath != "time" || name != "Duration" || len(x.Args) != 1 {
+			return true
+		}
+		if _, ok := x.Args[0].(*ast.BasicLit); ok {
+			c.ctxt.mark(n, &c.conversion)
+		}
+	}
+	return true
+}
+
+func (c *durationLitChecker) isUnit(x ast.Expr) bool {
+	pkgPath, name := qualifiedIdent(c.ctxt.info, x)
+	if pkgPath != "time" {
+		return false
+	}
+	switch name {
+	case "Nanosecond", "Microsecond", "Millisecond", "Second", "Minute", "Hour":
+		return true
+	default:
+		return false
+	}
+}
+
+type sizedSliceMakeChecker struct {
+	checkerBase
+
+	length   opVariant
+	capacity opVariant
+}
+
+func newSizedSliceMakeChecker(ctxt *context) checker {
+	c := &sizedSliceMakeChecker{}
+	c.ctxt = ctxt
+	c.length.warning = "use make([]T, n)"
+	c.capacity.warning = "use make([]T, 0, n)"
+	c.op = &operation{
+		name:     "sized slice make",
+		doc:      "make with length vs make with capacity",
+		variants: []*opVariant{&c.length, &c.capacity},
+		pedantic: true,
+	}
+	return c
+}
+
+func (c *sizedSliceMakeChecker) Visit(n ast.Node) bool {
+	call, ok := n.(*ast.CallExpr)
+	if !ok || len(call.Args) < 2 || !isBuiltin(c.ctxt.info, call.Fun, "make") {
+		return true
+	}
+	if _, ok := c.ctxt.info.TypeOf(call.Args[0]).(*types.Slice); !ok {
+		return true
+	}
+	switch {
+	case len(call.Args) == 2 && valueOf(call.Args[1]) != "0":
+		c.ctxt.mark(n, &c.length)
+	case len(call.Args) == 3 && valueOf(call.Args[1]) == "0":
+		c.ctxt.mark(n, &c.capacity)
+	}
+	return true
+}
+
+type rangeValueChecker struct {
+	checkerBase
+
+	indexOnly opVariant
+	withValue opVariant
+}
+
+func newRangeValueChecker(ctxt *context) checker {
+	c := &rangeValueChecker{}
+	c.ctxt = ctxt
+	c.indexOnly.warning = "use index-only loop, like in `for i := range s { use(s[i]) }`"
+	c.withValue.warning = "use value loop, like in `for i, v := range s { use(v) }`"
+	c.op = &operation{
+		name:     "range value",
+		doc:      "index-only vs value range loops over slices",
+		variants: []*opVariant{&c.indexOnly, &c.withValue},
+		pedantic: true,
+	}
+	return c
+}
+
+func (c *rangeValueChecker) Visit(n ast.Node) bool {
+	loop, ok := n.(*ast.RangeStmt)
+	if !ok || loop.Tok != token.DEFINE {
+		return true
+	}
+	switch underlyingOf(c.ctxt.info, loop.X).(type) {
+	case *types.Slice, *types.Array:
+	default:
+		return true
+	}
+	if loop.Value != nil {
+		if astcast.ToIdent(loop.Value).Name != "_" {
+			c.ctxt.mark(n, &c.withValue)
+		}
+		return true
+	}
+	key := astcast.ToIdent(loop.Key)
+	if key.Name == "" || key.Name == "_" {
+		return true
+	}
+	if c.firstUseIsIndex(loop, c.ctxt.info.ObjectOf(key)) {
+		c.ctxt.mark(n, &c.indexOnly)
+	}
+	return true
+}
+
+// firstUseIsIndex reports whether the first key usage inside
+// the loop body is exactly `x[key]`, where x is the ranged expression.
+func (c *rangeValueChecker) firstUseIsIndex(loop *ast.RangeStmt, key types.Object) bool {
+	found, isIndex := false, false
+	ast.Inspect(loop.Body, func(n ast.Node) bool {
+		if found {
+			return false
+		}
+		switch n := n.(type) {
+		case *ast.IndexExpr:
+			id, ok := n.Index.(*ast.Ident)
+			if ok && c.ctxt.info.ObjectOf(id) == key && astequal.Expr(n.X, loop.X) {
+				found, isIndex = true, true
+			}
+		case *ast.Ident:
+			if c.ctxt.info.ObjectOf(n) == key {
+				found = true
+			}
+		}
+		return !found
+	})
+	return isIndex
+}
+
+type chanDrainChecker struct {
+	checkerBase
+
+	rangeLoop opVariant
+	recvLoop  opVariant
+}
+
+func newChanDrainChecker(ctxt *context) checker {
+	c := &chanDrainChecker{}
+	c.ctxt = ctxt
+	c.rangeLoop.warning = "use range loop, like in `for range ch {}`"
+	c.recvLoop.warning = "use receive loop, like in `for { if _, ok := <-ch; !ok { break } }`"
+	c.op = &operation{
+		name:     "chan drain",
+		doc:      "range vs receive loops for channel draining",
+		variants: []*opVariant{&c.rangeLoop, &c.recvLoop},
+		pedantic: true,
+	}
+	return c
+}
+
+func (c *chanDrainChecker) Visit(n ast.Node) bool {
+	switch n := n.(type) {
+	case *ast.RangeStmt:
+		if len(n.Body.List) != 0 || n.Value != nil {
+			return true
+		}
+		if n.Key != nil && astcast.ToIdent(n.Key).Name != "_" {
+			return true
+		}
+		if _, ok := underlyingOf(c.ctxt.info, n.X).(*types.Chan); ok {
+			c.ctxt.mark(n, &c.rangeLoop)
+		}
+	case *ast.ForStmt:
+		if n.Init != nil || n.Cond != nil || n.Post != nil || len(n.Body.List) != 1 {
+			return true
+		}
+		if c.isDrainStmt(n.Body.List[0]) {
+			c.ctxt.mark(n, &c.recvLoop)
+		}
+	}
+	return true
+}
+
+// isDrainStmt reports whether stmt is the only statement of a loop
+// that drains a channel without using received values.
+//
+// Recognized shapes are `<-ch` and `if _, ok := <-ch; !ok { break }`.
+func (c *chanDrainChecker) isDrainStmt(stmt ast.Stmt) bool {
+	switch stmt := stmt.(type) {
+	case *ast.ExprStmt:
+		return c.isRecv(stmt.X)
+	case *ast.IfStmt:
+		init, ok := stmt.Init.(*ast.AssignStmt)
+		if !ok || stmt.Else != nil || len(init.Lhs) != 2 || len(init.Rhs) != 1 {
+			return false
+		}
+		if astcast.ToIdent(init.Lhs[0]).Name != "_" || !c.isRecv(init.Rhs[0]) {
+			return false
+		}
+		okVar := astcast.ToIdent(init.Lhs[1])
+		cond := astcast.ToUnaryExpr(stmt.Cond)
+		if okVar.Name == "" || cond.Op != token.NOT || astcast.ToIdent(cond.X).Name != okVar.Name {
+			return false
+		}
+		if len(stmt.Body.List) != 1 {
+			return false
+		}
+		br, ok := stmt.Body.List[0].(*ast.BranchStmt)
+		return ok && br.Tok == token.BREAK && br.Label == nil
+	default:
+		return false
+	}
+}
+
+func (c *chanDrainChecker) isRecv(x ast.Expr) bool {
+	return astcast.ToUnaryExpr(x).Op == token.ARROW
+}
+
+type printlnChecker struct {
+	checkerBase
+
+	println opVariant
+	printf  opVariant
+}
+
+func newPrintlnChecker(ctxt *context) checker {
+	c := &printlnChecker{}
+	c.ctxt = ctxt
+	c.println.warning = "use Println, like in `fmt.Println(\"x\")`"
+	c.printf.warning = "use Printf, like in `fmt.Printf(\"x\\n\")`"
+	c.op = &operation{
+		name:     "println",
+		doc:      "Println vs Printf with a trailing newline",
+		variants: []*opVariant{&c.println, &c.printf},
+		pedantic: true,
+	}
+	return c
+}
+
+func (c *printlnChecker) Visit(n ast.Node) bool {
+	call, ok := n.(*ast.CallExpr)
+	if !ok || len(call.Args) != 1 {
+		return true
+	}
+	lit := astcast.ToBasicLit(call.Args[0])
+	if lit.Kind != token.STRING {
+		return true
+	}
+	switch pkgPath, name := qualifiedIdent(c.ctxt.info, call.Fun); {
+	case pkgPath != "fmt":
+		return true
+	case name == "Println":
+		c.ctxt.mark(n, &c.println)
+	case name == "Printf":
+		format, err := strconv.Unquote(lit.Value)
+		if err == nil && !strings.Contains(format, "%") && strings.HasSuffix(format, "\n") {
+			c.ctxt.mark(n, &c.printf)
+		}
+	}
+	return true
+}
+
+type voidReturnChecker struct {
+	checkerBase
+
+	implicit opVariant
+	explicit opVariant
+}
+
+func newVoidReturnChecker(ctxt *context) checker {
+	c := &voidReturnChecker{}
+	c.ctxt = ctxt
+	c.implicit.warning = "omit trailing `return` in void functions"
+	c.explicit.warning = "end void functions with explicit `return`"
+	c.op = &operation{
+		name:     "void return",
+		doc:      "omitted vs explicit trailing return in void functions",
+		variants: []*opVariant{&c.implicit, &c.explicit},
+		pedantic: true,
+	}
+	return c
+}
+
+func (c *voidReturnChecker) Visit(n ast.Node) bool {
+	decl, ok := n.(*ast.FuncDecl)
+	if !ok || decl.Body == nil || len(decl.Body.List) == 0 {
+		return true
+	}
+	if decl.Type.Results != nil && len(decl.Type.Results.List) != 0 {
+		return true
+	}
+	last := decl.Body.List[len(decl.Body.List)-1]
+	switch last := last.(type) {
+	case *ast.ReturnStmt:
+		c.ctxt.mark(last, &c.explicit)
+	case *ast.ExprStmt:
+		call := astcast.ToCallExpr(last.X)
+		if astcast.ToIdent(call.Fun).Name != "panic" {
+			c.ctxt.mark(last, &c.implicit)
+		}
+	case *ast.AssignStmt, *ast.IncDecStmt, *ast.SendStmt, *ast.DeclStmt, *ast.GoStmt, *ast.DeferStmt:
+		c.ctxt.mark(last, &c.implicit)
+	}
+	// Other statements, like loops and conditionals, are not checked.
+	return true
+}
+
+type importAliasChecker struct {
+	checkerBase
+
+	pkgName    opVariant
+	alias      opVariant
+	otherAlias opVariant
+
+	// aliases maps the scoped import paths to their first seen aliases.
+	aliases map[string]string
+}
+
+func newImportAliasChecker(ctxt *context) checker {
+	c := &importAliasChecker{aliases: make(map[string]string)}
+	c.ctxt = ctxt
+	c.pkgName.warning = "import this package without alias, like other files do"
+	c.alias.warning = "import this package with alias, like other files do"
+	c.otherAlias.warning = "import this package with another alias, like other files do"
+	c.op = &operation{
+		name:     "import alias",
+		doc:      "aliased vs plain imports of the same package",
+		variants: []*opVariant{&c.pkgName, &c.alias, &c.otherAlias},
+	}
+	return c
+}
+
+func (c *importAliasChecker) Visit(n ast.Node) bool {
+	spec, ok := n.(*ast.ImportSpec)
+	if !ok {
+		return true
+	}
+	path, err := strconv.Unquote(spec.Path.Value)
+	if err != nil {
+		return false
+	}
+	// Every import path is inferred separately.
+	if spec.Name == nil {
+		c.ctxt.markScoped(n, &c.pkgName, path)
+		return false
+	}
+	if spec.Name.Name == "_" || spec.Name.Name == "." {
+		return false
+	}
+	pkgName, ok := c.ctxt.info.Defs[spec.Name].(*types.PkgName)
+	if !ok {
+		return false
+	}
+	if pkgName.Name() == pkgName.Imported().Name() {
+		c.ctxt.markScoped(n, &c.pkgName, path)
+		return false
+	}
+	// The first seen alias is the alias variant, so the files
+	// that use different aliases for the same path are flagged.
+	key := c.ctxt.scopeKeyOf(c.ctxt.opScopeID(c.op)) + "\x00" + path
+	first, ok := c.aliases[key]
+	if !ok {
+		first = pkgName.Name()
+		c.aliases[key] = first
+	}
+	if pkgName.Name() == first {
+		c.ctxt.markScoped(n, &c.alias, path)
+	} else {
+		c.ctxt.markScoped(n, &c.otherAlias, path)
+	}
+	return false
+}
+
+type blankImportChecker struct {
+	checkerBase
+
+	separate opVariant
+	inline   opVariant
+}
+
+func newBlankImportChecker(ctxt *context) checker {
+	c := &blankImportChecker{}
+	c.ctxt = ctxt
+	c.separate.warning = "put blank imports into their own group"
+	c.inline.warning = "put blank imports in line with other imports"
+	c.op = &operation{
+		name:     "blank import",
+		doc:      "separate vs inline groups for blank imports",
+		variants: []*opVariant{&c.separate, &c.inline},
+		pedantic: true,
+	}
+	return c
+}
+
+func (c *blankImportChecker) Visit(n ast.Node) bool {
+	decl, ok := n.(*ast.GenDecl)
+	if !ok || decl.Tok != token.IMPORT || len(decl.Specs) < 2 {
+		return false
+	}
+
+	// Import specs are grouped by the empty lines between them.
+	// Blank imports are separate if no group contains both
+	// blank and regular imports.
+	var firstBlank ast.Node
+	hasRegular := false
+	groupBlank, groupRegular := false, false
+	prevLine := 0
+	for _, spec := range decl.Specs {
+		spec := spec.(*ast.ImportSpec)
+		start := spec.Pos()
+		if spec.Doc != nil {
+			start = spec.Doc.Pos()
+		}
+		line := c.ctxt.fset.Position(start).Line
+		if prevLine != 0 && line > prevLine+1 {
+			groupBlank, groupRegular = false, false
+		}
+		prevLine = c.ctxt.fset.Position(spec.End()).Line
+
+		if spec.Name != nil && spec.Name.Name == "_" {
+			groupBlank = true
+			if firstBlank == nil {
+				firstBlank = spec
+			}
+		} else {
+			groupRegular = true
+			hasRegular = true
+		}
+		if groupBlank && groupRegular {
+			c.ctxt.mark(spec, &c.inline)
+			return false
+		}
+	}
+	if firstBlank != nil && hasRegular {
+		c.ctxt.mark(firstBlank, &c.separate)
+	}
+	return false
+}
+
+// defaultChainDepth is a default value of the -chain-depth flag.
+const defaultChainDepth = 3
+
+type methodChainChecker struct {
+	checkerBase
+
+	chain opVariant
+	split opVariant
+
+	depth int
+}
+
+func newMethodChainChecker(ctxt *context) checker {
+	c := &methodChainChecker{depth: ctxt.flags.chainDepth}
+	if c.depth == 0 {
+		c.depth = defaultChainDepth
+	}
+	c.ctxt = ctxt
+	c.chain.warning = "chain method calls, like in `x.A().B().C()`"
+	c.split.warning = "assign method call results to variables, like in `a := x.A(); b := a.B()`"
+	c.op = &operation{
+		name:     "method chain",
+		doc:      "chained method calls vs intermediate variables",
+		variants: []*opVariant{&c.chain, &c.split},
+		pedantic: true,
+	}
+	return c
+}
+
+func (c *methodChainChecker) Visit(n ast.Node) bool {
+	if call, ok := n.(*ast.CallExpr); ok {
+		if !c.isChainPart(call) && c.chainDepth(call) >= c.depth {
+			c.ctxt.mark(n, &c.chain)
+		}
+		return true
+	}
+
+	list := stmtList(n)
+	for i := 0; i < len(list); i++ {
+		// Find a run of assignments where every method call receiver
+		// is a variable that is assigned by the previous statement.
+		lhs, call := c.splitStep(list[i])
+		if call == nil || c.chainDepth(call) != 1 {
+			continue
+		}
+		j := i + 1
+		for ; j < len(list); j++ {
+			nextLHS, next := c.splitStep(list[j])
+			if next == nil || c.chainDepth(next) != 1 {
+				break
+			}
+			recv := astcast.ToIdent(astcast.ToSelectorExpr(next.Fun).X)
+			if c.ctxt.info.ObjectOf(recv) != c.ctxt.info.ObjectOf(lhs) {
+				break
+			}
+			lhs = nextLHS
+		}
+		if j-i >= c.depth {
+			c.ctxt.mark(list[i], &c.split)
+		}
+		i = j - 1
+	}
+	return true
+}
+
+// isChainPart reports whether call is a receiver of another method call.
+func (c *methodChainChecker) isChainPart(call *ast.CallExpr) bool {
+	sel, ok := c.ctxt.astinfo.Parents[call].(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	parent, ok := c.ctxt.astinfo.Parents[sel].(*ast.CallExpr)
+	return ok && parent.Fun == sel
+}
+
+// chainDepth returns a number of chained method calls in x.
+func (c *methodChainChecker) chainDepth(x ast.Expr) int {
+	call, ok := x.(*ast.CallExpr)
+	if !ok {
+		return 0
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return 0
+	}
+	if s := c.ctxt.info.Selections[sel]; s == nil || s.Kind() != types.MethodVal {
+		return 0
+	}
+	return 1 + c.chainDepth(sel.X)
+}
+
+// splitStep matches `v := x.M()` and `v = x.M()` statements.
+func (c *methodChainChecker) splitStep(stmt ast.Stmt) (lhs *ast.Ident, call *ast.CallExpr) {
+	assign, ok := stmt.(*ast.AssignStmt)
+	if !ok || len(assign.Lhs) != 1 || len(assign.Rhs) != 1 {
+		return nil, nil
+	}
+	if assign.Tok != token.DEFINE && assign.Tok != token.ASSIGN {
+		return nil, nil
+	}
+	lhs, ok = assign.Lhs[0].(*ast.Ident)
+	if !ok || lhs.Name == "_" {
+		return nil, nil
+	}
+	call, ok = assign.Rhs[0].(*ast.CallExpr)
+	if !ok {
+		return nil, nil
+	}
+	return lhs, call
+}
+
+type fieldsSplitChecker struct {
+	checkerBase
+
+	fields opVariant
+	split  opVariant
+}
+
+func newFieldsSplitChecker(ctxt *context) checker {
+	c := &fieldsSplitChecker{}
+	c.ctxt = ctxt
+	c.fields.warning = "use strings.Fields(s) for whitespace splitting"
+	c.split.warning = "use strings.Split(s, \" \") for whitespace splitting"
+	c.op = &operation{
+		name:     "fields split",
+		doc:      "strings.Fields vs strings.Split for whitespace splitting",
+		variants: []*opVariant{&c.fields, &c.split},
+		pedantic: true,
+	}
+	return c
+}
+
+func (c *fieldsSplitChecker) Visit(n ast.Node) bool {
+	call, ok := n.(*ast.CallExpr)
+	if !ok {
+		return true
+	}
+	switch pkgPath, name := qualifiedIdent(c.ctxt.info, call.Fun); {
+	case pkgPath != "strings":
+		return true
+	case name == "Fields" && len(call.Args) == 1:
+		c.ctxt.mark(n, &c.fields)
+	case name == "Split" && len(call.Args) == 2:
+		lit := astcast.ToBasicLit(call.Args[1])
+		if lit.Kind != token.STRING {
+			return true
+		}
+		sep, err := strconv.Unquote(lit.Value)
+		if err == nil && (sep == " " || sep == "\t" || sep == "\n") {
+			c.ctxt.mark(n, &c.split)
+		}
+	}
+	return true
+}
+
+type sliceDeleteChecker struct {
+	checkerBase
+
+	appendTrick opVariant
+	shiftLoop   opVariant
+}
+
+func newSliceDeleteChecker(ctxt *context) checker {
+	c := &sliceDeleteChecker{}
+	c.ctxt = ctxt
+	c.appendTrick.warning = "use `s = append(s[:i], s[i+1:]...)` to delete element"
+	c.shiftLoop.warning = "use shifting loop followed by `s = s[:len(s)-1]` to delete element"
+	c.op = &operation{
+		name:     "slice delete",
+		doc:      "append vs shifting loop for slice element deletion",
+		variants: []*opVariant{&c.appendTrick, &c.shiftLoop},
+		pedantic: true,
+	}
+	return c
+}
+
+func (c *sliceDeleteChecker) Visit(n ast.Node) bool {
+	list := stmtList(n)
+	for i, stmt := range list {
+		switch stmt := stmt.(type) {
+		case *ast.AssignStmt:
+			if c.isAppendTrick(stmt) {
+				c.ctxt.mark(stmt, &c.appendTrick)
+			}
+		case *ast.ForStmt:
+			if i+1 < len(list) && c.isShiftLoop(stmt, list[i+1]) {
+				c.ctxt.mark(stmt, &c.shiftLoop)
+			}
+		}
+	}
+	return true
+}
+
+// isAppendTrick matches `s = append(s[:i], s[i+1:]...)`.
+func (c *sliceDeleteChecker) isAppendTrick(assign *ast.AssignStmt) bool {
+	if assign.Tok != token.ASSIGN || len(assign.Lhs) != 1 || len(assign.Rhs) != 1 {
+		return false
+	}
+	call := astcast.ToCallExpr(assign.Rhs[0])
+	if len(call.Args) != 2 || call.Ellipsis == token.NoPos || !isBuiltin(c.ctxt.info, call.Fun, "append") {
+		return false
+	}
+	s := assign.Lhs[0]
+	head := astcast.ToSliceExpr(call.Args[0])
+	tail := astcast.ToSliceExpr(call.Args[1])
+	if head.Low != nil || head.High == nil || head.Slice3 || tail.High != nil {
+		return false
+	}
+	return astequal.Expr(head.X, s) &&
+		astequal.Expr(tail.X, s) &&
+		c.isNext(tail.Low, head.High)
+}
+
+// isShiftLoop matches a loop followed by a slice truncation:
+//
+//	for j := i; j < len(s)-1; j++ {
+//		s[j] = s[j+1]
+//	}
+//	s = s[:len(s)-1]
+func (c *sliceDeleteChecker) isShiftLoop(loop *ast.ForStmt, next ast.Stmt) bool {
+	init := astcast.ToAssignStmt(loop.Init)
+	if init.Tok != token.DEFINE || len(init.Lhs) != 1 || len(init.Rhs) != 1 {
+		return false
+	}
+	j := astcast.ToIdent(init.Lhs[0])
+	post := astcast.ToIncDecStmt(loop.Post)
+	if post.Tok != token.INC || astcast.ToIdent(post.X).Name != j.Name {
+		return false
+	}
+	if loop.Body == nil || len(loop.Body.List) != 1 {
+		return false
+	}
+	shift := astcast.ToAssignStmt(loop.Body.List[0])
+	if shift.Tok != token.ASSIGN || len(shift.Lhs) != 1 || len(shift.Rhs) != 1 {
+		return false
+	}
+	dst := astcast.ToIndexExpr(shift.Lhs[0])
+	src := astcast.ToIndexExpr(shift.Rhs[0])
+	s := dst.X
+	if s == nil || astcast.ToIdent(dst.Index).Name != j.Name {
+		return false
+	}
+	if !astequal.Expr(src.X, s) || !c.isNext(src.Index, dst.Index) {
+		return false
+	}
+	cond := astcast.ToBinaryExpr(loop.Cond)
+	if cond.Op != token.LSS || astcast.ToIdent(cond.X).Name != j.Name || !c.isLenMinusOne(cond.Y, s) {
+		return false
+	}
+
+	truncate := astcast.ToAssignStmt(next)
+	if truncate.Tok != token.ASSIGN || len(truncate.Lhs) != 1 || len(truncate.Rhs) != 1 {
+		return false
+	}
+	slice := astcast.ToSliceExpr(truncate.Rhs[0])
+	return astequal.Expr(truncate.Lhs[0], s) &&
+		astequal.Expr(slice.X, s) &&
+		slice.Low == nil && !slice.Slice3 &&
+		c.isLenMinusOne(slice.High, s)
+}
+
+// isNext matches `x+1` expression.
+func (c *sliceDeleteChecker) isNext(next, x ast.Expr) bool {
+	add := astcast.ToBinaryExpr(next)
+	return add.Op == token.ADD && astequal.Expr(add.X, x) && valueOf(add.Y) == "1"
+}
+
+// isLenMinusOne matches `len(s)-1` expression.
+func (c *sliceDeleteChecker) isLenMinusOne(x, s ast.Expr) bool {
+	sub := astcast.ToBinaryExpr(x)
+	if sub.Op != token.SUB || valueOf(sub.Y) != "1" {
+		return false
+	}
+	call := astcast.ToCallExpr(sub.X)
+	return len(call.Args) == 1 && isBuiltin(c.ctxt.info, call.Fun, "len") && astequal.Expr(call.Args[0], s)
+}
+
+type mapInitChecker struct {
+	checkerBase
+
+	mapLit     opVariant
+	makeAssign opVariant
+}
+
+func newMapInitChecker(ctxt *context) checker {
+	c := &mapInitChecker{}
+	c.ctxt = ctxt
+	c.mapLit.warning = "use map literal, like in `m := map[K]V{k: v}`"
+	c.makeAssign.warning = "use make and assignments, like in `m := make(map[K]V); m[k] = v`"
+	c.op = &operation{
+		name:     "map init",
+		doc:      "map literals vs make and assignments",
+		variants: []*opVariant{&c.mapLit, &c.makeAssign},
+		pedantic: true,
+	}
+	return c
+}
+
+func (c *mapInitChecker) Visit(n ast.Node) bool {
+	list := stmtList(n)
+	for i, stmt := range list {
+		assign, ok := stmt.(*ast.AssignStmt)
+		if !ok || assign.Tok != token.DEFINE || len(assign.Lhs) != 1 || len(assign.Rhs) != 1 {
+			continue
+		}
+		m := astcast.ToIdent(assign.Lhs[0])
+		if _, ok := c.ctxt.info.TypeOf(assign.Rhs[0]).(*types.Map); !ok {
+			continue
+		}
+		switch rhs := assign.Rhs[0].(type) {
+		case *ast.CompositeLit:
+			if len(rhs.Elts) != 0 {
+				c.ctxt.mark(stmt, &c.mapLit)
+			}
+		case *ast.CallExpr:
+			if !isBuiltin(c.ctxt.info, rhs.Fun, "make") {
+				continue
+			}
+			if i+1 < len(list) && c.isConstKeyAssign(list[i+1], m) {
+				c.ctxt.mark(stmt, &c.makeAssign)
+			}
+		}
+	}
+	return true
+}
+
+// isConstKeyAssign matches `m[k] = v` where k is a constant expression.
+func (c *mapInitChecker) isConstKeyAssign(stmt ast.Stmt, m *ast.Ident) bool {
+	assign := astcast.ToAssignStmt(stmt)
+	if assign.Tok != token.ASSIGN || len(assign.Lhs) != 1 || len(assign.Rhs) != 1 {
+		return false
+	}
+	index := astcast.ToIndexExpr(assign.Lhs[0])
+	obj := c.ctxt.info.ObjectOf(m)
+	if obj == nil || c.ctxt.info.ObjectOf(astcast.ToIdent(index.X)) != obj {
+		return false
+	}
+	return c.ctxt.info.Types[index.Index].Value != nil
+}
+
+type sliceInitChecker struct {
+	checkerBase
+
+	sliceLit   opVariant
+	makeAppend opVariant
+}
+
+func newSliceInitChecker(ctxt *context) checker {
+	c := &sliceInitChecker{}
+	c.ctxt = ctxt
+	c.sliceLit.warning = "use slice literal, like in `s := []T{a, b}`"
+	c.makeAppend.warning = "use append, like in `s := make([]T, 0, 2); s = append(s, a, b)`"
+	c.op = &operation{
+		name:     "slice init",
+		doc:      "slice literals vs make and append",
+		variants: []*opVariant{&c.sliceLit, &c.makeAppend},
+		pedantic: true,
+	}
+	return c
+}
+
+func (c *sliceInitChecker) Visit(n ast.Node) bool {
+	list := stmtList(n)
+	for i, stmt := range list {
+		if decl, ok := stmt.(*ast.DeclStmt); ok {
+			// `var s []T` followed by append.
+			s := c.nilSliceVar(decl)
+			if s != nil && i+1 < len(list) && c.isAppendElems(list[i+1], s) {
+				c.ctxt.mark(stmt, &c.makeAppend)
+			}
+			continue
+		}
+		assign, ok := stmt.(*ast.AssignStmt)
+		if !ok || assign.Tok != token.DEFINE || len(assign.Lhs) != 1 || len(assign.Rhs) != 1 {
+			continue
+		}
+		if _, ok := c.ctxt.info.TypeOf(assign.Rhs[0]).(*types.Slice); !ok {
+			continue
+		}
+		switch rhs := assign.Rhs[0].(type) {
+		case *ast.CompositeLit:
+			if len(rhs.Elts) != 0 {
+				c.ctxt.mark(stmt, &c.sliceLit)
+			}
+		case *ast.CallExpr:
+			// Only `make([]T, 0, n)` is expected.
+			if !isBuiltin(c.ctxt.info, rhs.Fun, "make") || len(rhs.Args) != 3 || valueOf(rhs.Args[1]) != "0" {
+				continue
+			}
+			s := c.ctxt.info.ObjectOf(astcast.ToIdent(assign.Lhs[0]))
+			if i+1 < len(list) && c.isAppendElems(list[i+1], s) {
+				c.ctxt.mark(stmt, &c.makeAppend)
+			}
+		}
+	}
+	return true
+}
+
+// nilSliceVar returns a variable declared by `var s []T`, if any.
+func (c *sliceInitChecker) nilSliceVar(decl *ast.DeclStmt) types.Object {
+	gen, ok := decl.Decl.(*ast.GenDecl)
+	if !ok || gen.Tok != token.VAR || len(gen.Specs) != 1 {
+		return nil
+	}
+	spec := gen.Specs[0].(*ast.ValueSpec)
+	if len(spec.Names) != 1 || len(spec.Values) != 0 {
+		return nil
+	}
+	obj := c.ctxt.info.ObjectOf(spec.Names[0])
+	if obj == nil {
+		return nil
+	}
+	if _, ok := obj.Type().Underlying().(*types.Slice); !ok {
+		return nil
+	}
+	return obj
+}
+
+// isAppendElems matches `s = append(s, elems...)` without a spread argument.
+func (c *sliceInitChecker) isAppendElems(stmt ast.Stmt, s types.Object) bool {
+	assign := astcast.ToAssignStmt(stmt)
+	if s == nil || assign.Tok != token.ASSIGN || len(assign.Lhs) != 1 || len(assign.Rhs) != 1 {
+		return false
+	}
+	call := astcast.ToCallExpr(assign.Rhs[0])
+	if !isBuiltin(c.ctxt.info, call.Fun, "append") || len(call.Args) < 2 || call.Ellipsis != token.NoPos {
+		return false
+	}
+	info := c.ctxt.info
+	return info.ObjectOf(astcast.ToIdent(assign.Lhs[0])) == s &&
+		info.ObjectOf(astcast.ToIdent(call.Args[0])) == s
+}
+
+type deferCloseChecker struct {
+	checkerBase
+
+	deferred opVariant
+	manual   opVariant
+}
+
+func newDeferCloseChecker(ctxt *context) checker {
+	c := &deferCloseChecker{}
+	c.ctxt = ctxt
+	c.deferred.warning = "defer Close right after the file is opened"
+	c.manual.warning = "call Close at the end of the block instead of defer"
+	c.op = &operation{
+		name:     "defer close",
+		doc:      "deferred vs explicit Close calls for opened files",
+		variants: []*opVariant{&c.deferred, &c.manual},
+		pedantic: true,
+	}
+	return c
+}
+
+func (c *deferCloseChecker) Visit(n ast.Node) bool {
+	list := stmtList(n)
+	for i, stmt := range list {
+		f := c.openedFile(stmt)
+		if f == nil {
+			continue
+		}
+		rest := list[i+1:]
+		if len(rest) != 0 && c.isErrCheck(rest[0]) {
+			rest = rest[1:]
+		}
+		if len(rest) == 0 {
+			continue
+		}
+		if c.isCloseCall(rest[0], f, true) {
+			c.ctxt.mark(stmt, &c.deferred)
+			continue
+		}
+		// Manual Close is expected to be the last statement,
+		// optionally followed by a return.
+		last := len(rest) - 1
+		if _, ok := rest[last].(*ast.ReturnStmt); ok && last > 0 {
+			last--
+		}
+		if !c.isCloseCall(rest[last], f, false) {
+			continue
+		}
+		hasDefer := false
+		for _, stmt := range rest {
+			if c.isCloseCall(stmt, f, true) {
+				hasDefer = true
+			}
+		}
+		if !hasDefer {
+			c.ctxt.mark(stmt, &c.manual)
+		}
+	}
+	return true
+}
+
+// openedFile matches `f, err := os.Open(...)` and returns an f object.
+// os.Create and os.OpenFile are recognized as well.
+func (c *deferCloseChecker) openedFile(stmt ast.Stmt) types.Object {
+	assign, ok := stmt.(*ast.AssignStmt)
+	if !ok || len(assign.Lhs) != 2 || len(assign.Rhs) != 1 {
+		return nil
+	}
+	call := astcast.ToCallExpr(assign.Rhs[0])
+	switch pkgPath, name := qualifiedIdent(c.ctxt.info, call.Fun); {
+	case pkgPath != "os":
+		return nil
+	case name == "Open", name == "Create", name == "OpenFile":
+		return c.ctxt.info.ObjectOf(astcast.ToIdent(assign.Lhs[0]))
+	default:
+		return nil
+	}
+}
+
+// isErrCheck matches `if err != nil {...}` statement.
+func (c *deferCloseChecker) isErrCheck(stmt ast.Stmt) bool {
+	ifStmt, ok := stmt.(*ast.IfStmt)
+	return ok && ifStmt.Init == nil && isErrCheck(c.ctxt.info, ifStmt.Cond)
+}
+
+// isCloseCall matches `f.Close()` call statement.
+// If deferred is true, only `defer f.Close()` is matched.
+func (c *deferCloseChecker) isCloseCall(stmt ast.Stmt, f types.Object, deferred bool) bool {
+	var call *ast.CallExpr
+	switch stmt := stmt.(type) {
+	case *ast.DeferStmt:
+		if !deferred {
+			return false
+		}
+		call = stmt.Call
+	case *ast.ExprStmt:
+		if deferred {
+			return false
+		}
+		call = astcast.ToCallExpr(stmt.X)
+	default:
+		return false
+	}
+	sel := astcast.ToSelectorExpr(call.Fun)
+	return len(call.Args) == 0 &&
+		sel.Sel != nil && sel.Sel.Name == "Close" &&
+		f != nil && c.ctxt.info.ObjectOf(astcast.ToIdent(sel.X)) == f
+}
+
+type lineReaderChecker struct {
+	checkerBase
+
+	scanner opVariant
+	reader  opVariant
+}
+
+func newLineReaderChecker(ctxt *context) checker {
+	c := &lineReaderChecker{}
+	c.ctxt = ctxt
+	c.scanner.warning = "use bufio.NewScanner for reading lines"
+	c.reader.warning = "use bufio.NewReader for reading lines"
+	c.op = &operation{
+		name:     "line reader",
+		doc:      "bufio.Scanner vs bufio.Reader for reading lines",
+		variants: []*opVariant{&c.scanner, &c.reader},
+		pedantic: true,
+	}
+	return c
+}
+
+func (c *lineReaderChecker) Visit(n ast.Node) bool {
+	call, ok := n.(*ast.CallExpr)
+	if !ok {
+		return true
+	}
+	switch pkgPath, name := qualifiedIdent(c.ctxt.info, call.Fun); {
+	case pkgPath != "bufio":
+		return true
+	case name == "NewScanner":
+		c.ctxt.mark(n, &c.scanner)
+	case name == "NewReader":
+		c.ctxt.mark(n, &c.reader)
+	}
+	return true
+}
+
+// convSpellingChecker detects conversions to the type that
+// can be spelled using either alias or its original name, like byte and uint8.
+type convSpellingChecker struct {
+	checkerBase
+
+	alias opVariant
+	orig  opVariant
+
+	aliasName string
+	origName  string
+}
+
+func newByteConvChecker(ctxt *context) checker {
+	return newConvSpellingChecker(ctxt, "byte conv", "byte", "uint8")
+}
+
+func newRuneConvChecker(ctxt *context) checker {
+	return newConvSpellingChecker(ctxt, "rune conv", "rune", "int32")
+}
+
+func newConvSpellingChecker(ctxt *context, name, aliasName, origName string) checker {
+	c := &convSpellingChecker{aliasName: aliasName, origName: origName}
+	c.ctxt = ctxt
+	c.alias.warning = "use " + aliasName + " in conversions"
+	c.orig.warning = "use " + origName + " in conversions"
+	c.op = &operation{
+		name:     name,
+		doc:      aliasName + " vs " + origName + " in conversions",
+		variants: []*opVariant{&c.alias, &c.orig},
+	}
+	return c
+}
+
+func (c *convSpellingChecker) Visit(n ast.Node) bool {
+	call, ok := n.(*ast.CallExpr)
+	if !ok || len(call.Args) != 1 {
+		return true
+	}
+	// Only conversions that can't be confused with the numeric
+	// ones are checked: string conversions to slices and
+	// rune literal conversions.
+	typ := call.Fun
+	if slice, ok := typ.(*ast.ArrayType); ok && slice.Len == nil {
+		if !typep.HasStringKind(c.ctxt.info.TypeOf(call.Args[0])) {
+			return true
+		}
+		typ = slice.Elt
+	} else if astcast.ToBasicLit(call.Args[0]).Kind != token.CHAR {
+		return true
+	}
+	id, ok := typ.(*ast.Ident)
+	if !ok || c.ctxt.info.ObjectOf(id) != types.Universe.Lookup(id.Name) {
+		return true
+	}
+	switch id.Name {
+	case c.aliasName:
+		c.ctxt.mark(n, &c.alias)
+	case c.origName:
+		c.ctxt.mark(n, &c.orig)
+	}
+	return true
+}
+
+type errScopeChecker struct {
+	checkerBase
+
+	ifInit opVariant
+	reused opVariant
+}
+
+func newErrScopeChecker(ctxt *context) checker {
+	c := &errScopeChecker{}
+	c.ctxt = ctxt
+	c.ifInit.warning = "scope err to the if statement, like in `if err := f(); err != nil {}`"
+	c.reused.warning = "reuse err variable, like in `err = f(); if err != nil {}`"
+	c.op = &operation{
+		name:     "err scope",
+		doc:      "if-scoped vs reused err variables",
+		variants: []*opVariant{&c.ifInit, &c.reused},
+		pedantic: true,
+	}
+	return c
+}
+
+func (c *errScopeChecker) Visit(n ast.Node) bool {
+	var body *ast.BlockStmt
+	switch n := n.(type) {
+	case *ast.FuncDecl:
+		body = n.Body
+	case *ast.FuncLit:
+		body = n.Body
+	default:
+		return true
+	}
+	if body == nil {
+		return true
+	}
+
+	// Suggestions are inferred for every function separately.
+	key := c.ctxt.fset.Position(n.Pos()).String()
+	inspectFuncBody(body, func(n ast.Node) {
+		list := stmtList(n)
+		for i, stmt := range list {
+			ifStmt, ok := stmt.(*ast.IfStmt)
+			if !ok || ifStmt.Else != nil {
+				continue
+			}
+			if ifStmt.Init != nil {
+				if c.isErrCall(ifStmt.Init, token.DEFINE) && c.isErrCheck(ifStmt.Cond) {
+					c.ctxt.markScoped(ifStmt, &c.ifInit, key)
+				}
+				continue
+			}
+			if i > 0 && c.isErrCall(list[i-1], token.ASSIGN) && c.isErrCheck(ifStmt.Cond) {
+				c.ctxt.markScoped(list[i-1], &c.reused, key)
+			}
+		}
+	})
+	return true
+}
+
+// isErrCall matches `err := f()` or `err = f()` statement, depending on tok.
+func (c *errScopeChecker) isErrCall(stmt ast.Stmt, tok token.Token) bool {
+	assign, ok := stmt.(*ast.AssignStmt)
+	if !ok || assign.Tok != tok || len(assign.Lhs) != 1 || len(assign.Rhs) != 1 {
+		return false
+	}
+	if _, ok := assign.Rhs[0].(*ast.CallExpr); !ok {
+		return false
+	}
+	return c.isErrVar(assign.Lhs[0])
+}
+
+// isErrCheck matches `err != nil` expression.
+func (c *errScopeChecker) isErrCheck(cond ast.Expr) bool {
+	return isErrCheck(c.ctxt.info, cond) && c.isErrVar(astcast.ToBinaryExpr(cond).X)
+}
+
+func (c *errScopeChecker) isErrVar(x ast.Expr) bool {
+	id := astcast.ToIdent(x)
+	return id.Name == "err" && isErrorType(c.ctxt.info, id)
+}
+
+type errCompareChecker struct {
+	checkerBase
+
+	equality opVariant
+	errorsIs opVariant
+}
+
+func newErrCompareChecker(ctxt *context) checker {
+	c := &errCompareChecker{}
+	c.ctxt = ctxt
+	c.equality.warning = "compare errors with `err == ErrFoo`"
+	c.errorsIs.warning = "compare errors with `errors.Is(err, ErrFoo)`"
+	c.op = &operation{
+		name:     "err compare",
+		doc:      "== vs errors.Is for sentinel error comparisons",
+		variants: []*opVariant{&c.equality, &c.errorsIs},
+		pedantic: true,
+	}
+	// Unlike the equality, errors.Is works with wrapped errors,
+	// so it's suggested whenever both forms are mixed.
+	c.op.preferred = &c.errorsIs
+	return c
+}
+
+func (c *errCompareChecker) Visit(n ast.Node) bool {
+	switch n := n.(type) {
+	case *ast.BinaryExpr:
+		if n.Op != token.EQL && n.Op != token.NEQ {
+			return true
+		}
+		if isErrorType(c.ctxt.info, n.X) && c.isSentinel(n.Y) {
+			c.ctxt.mark(n, &c.equality)
+		}
+	case *ast.CallExpr:
+		pkgPath, name := qualifiedIdent(c.ctxt.info, n.Fun)
+		if pkgPath == "errors" && name == "Is" && len(n.Args) == 2 {
+			c.ctxt.mark(n, &c.errorsIs)
+		}
+	}
+	return true
+}
+
+// isSentinel reports whether x refers to a package-level error variable
+// named like ErrFoo or errFoo.
+func (c *errCompareChecker) isSentinel(x ast.Expr) bool {
+	var id *ast.Ident
+	switch x := x.(type) {
+	case *ast.Ident:
+		id = x
+	case *ast.SelectorExpr:
+		id = x.Sel
+	default:
+		return false
+	}
+	v, ok := c.ctxt.info.ObjectOf(id).(*types.Var)
+	if !ok || v.Pkg() == nil || v.Parent() != v.Pkg().Scope() || !isErrorType(c.ctxt.info, id) {
+		return false
+	}
+	name := id.Name
+	return len(name) > 3 &&
+		(strings.HasPrefix(name, "Err") || strings.HasPrefix(name, "err")) &&
+		strings.ToUpper(name[3:4]) == name[3:4]
+}
+
+type nilCheckChecker struct {
+	checkerBase
+
+	nilCompare opVariant
+	reflectNil opVariant
+}
+
+func newNilCheckChecker(ctxt *context) checker {
+	c := &nilCheckChecker{}
+	c.ctxt = ctxt
+	c.nilCompare.warning = "use `x == nil` instead of `reflect.ValueOf(x).IsNil()`"
+	c.reflectNil.warning = "use `reflect.ValueOf(x).IsNil()` instead of `x == nil`"
+	c.op = &operation{
+		name:     "nil check",
+		doc:      "== nil vs reflect IsNil checks",
+		variants: []*opVariant{&c.nilCompare, &c.reflectNil},
+		pedantic: true,
+	}
+	// Using reflection for the static types that can be compared
+	// to nil directly is needlessly complicated and slow.
+	c.op.forced = &c.nilCompare
+	return c
+}
+
+func (c *nilCheckChecker) Visit(n ast.Node) bool {
+	switch n := n.(type) {
+	case *ast.BinaryExpr:
+		if n.Op != token.EQL && n.Op != token.NEQ {
+			return true
+		}
+		if astcast.ToIdent(n.Y).Name == "nil" && c.isNillable(n.X) {
+			c.ctxt.mark(n, &c.nilCompare)
+		}
+	case *ast.CallExpr:
+		// Match `reflect.ValueOf(x).IsNil()`.
+		sel := astcast.ToSelectorExpr(n.Fun)
+		if len(n.Args) != 0 || sel.Sel == nil || sel.Sel.Name != "IsNil" {
+			return true
+		}
+		valueOf := astcast.ToCallExpr(sel.X)
+		pkgPath, name := qualifiedIdent(c.ctxt.info, valueOf.Fun)
+		if pkgPath == "reflect" && name == "ValueOf" && len(valueOf.Args) == 1 && c.isNillable(valueOf.Args[0]) {
+			c.ctxt.mark(n, &c.reflectNil)
+		}
+	}
+	return true
+}
+
+// isNillable reports whether x can be compared to nil and the result
+// is the same as for the reflect IsNil method.
+// Interfaces are not included, since they can hold typed nil values,
+// and neither are the expressions of unknown type.
+func (c *nilCheckChecker) isNillable(x ast.Expr) bool {
+	switch underlyingOf(c.ctxt.info, x).(type) {
+	case *types.Pointer, *types.Map, *types.Slice, *types.Chan, *types.Signature:
+		return true
+	default:
+		return false
+	}
+}
+
+type stringBuilderChecker struct {
+	checkerBase
+
+	builder opVariant
+	buffer  opVariant
+}
+
+func newStringBuilderChecker(ctxt *context) checker {
+	c := &stringBuilderChecker{}
+	c.ctxt = ctxt
+	c.builder.warning = "use strings.Builder to build strings"
+	c.buffer.warning = "use bytes.Buffer to build strings"
+	c.op = &operation{
+		name:     "string builder",
+		doc:      "strings.Builder vs bytes.Buffer for building strings",
+		variants: []*opVariant{&c.builder, &c.buffer},
+		pedantic: true,
+	}
+	// strings.Builder avoids the copying in the String method,
+	// so it's suggested whenever both types are used.
+	c.op.preferred = &c.builder
+	return c
+}
+
+func (c *stringBuilderChecker) Visit(n ast.Node) bool {
+	var body *ast.BlockStmt
+	switch n := n.(type) {
+	case *ast.FuncDecl:
+		body = n.Body
+	case *ast.FuncLit:
+		body = n.Body
+	default:
+		return true
+	}
+	if body == nil {
+		return true
+	}
+
+	inspectFuncBody(body, func(n ast.Node) {
+		obj, typ := c.bufferVar(n)
+		if obj == nil {
+			return
+		}
+		switch pkgPath, name := qualifiedIdent(c.ctxt.info, typ); {
+		case pkgPath == "strings" && name == "Builder":
+			c.ctxt.mark(n, &c.builder)
+		case pkgPath == "bytes" && name == "Buffer":
+			// Buffers that are not turned into a string are
+			// likely used for bytes and can't be replaced.
+			if c.hasStringCall(body, obj) {
+				c.ctxt.mark(n, &c.buffer)
+			}
+		}
+	})
+	return true
+}
+
+// bufferVar matches a local variable declaration and returns the
+// declared variable object along with its type expression.
+// Recognized forms are `var b T`, `b := T{}`, `b := &T{}` and `b := new(T)`.
+func (c *stringBuilderChecker) bufferVar(n ast.Node) (types.Object, ast.Expr) {
+	switch n := n.(type) {
+	case *ast.ValueSpec:
+		if len(n.Names) != 1 || len(n.Values) != 0 || n.Type == nil {
+			return nil, nil
+		}
+		return c.ctxt.info.ObjectOf(n.Names[0]), n.Type
+	case *ast.AssignStmt:
+		if n.Tok != token.DEFINE || len(n.Lhs) != 1 || len(n.Rhs) != 1 {
+			return nil, nil
+		}
+		var typ ast.Expr
+		switch x := n.Rhs[0].(type) {
+		case *ast.CompositeLit:
+			if len(x.Elts) == 0 {
+				typ = x.Type
+			}
+		case *ast.UnaryExpr:
+			lit := astcast.ToCompositeLit(x.X)
+			if x.Op == token.AND && len(lit.Elts) == 0 {
+				typ = lit.Type
+			}
+		case *ast.CallExpr:
+			if len(x.Args) == 1 && isBuiltin(c.ctxt.info, x.Fun, "new") {
+				typ = x.Args[0]
+			}
+		}
+		if typ == nil {
+			return nil, nil
+		}
+		return c.ctxt.info.ObjectOf(astcast.ToIdent(n.Lhs[0])), typ
+	default:
+		return nil, nil
+	}
+}
+
+// hasStringCall reports whether body contains `b.String()` call
+// where b refers to obj.
+func (c *stringBuilderChecker) hasStringCall(body *ast.BlockStmt, obj types.Object) bool {
+	found := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || len(call.Args) != 0 {
+			return !found
+		}
+		sel := astcast.ToSelectorExpr(call.Fun)
+		if sel.Sel != nil && sel.Sel.Name == "String" && c.ctxt.info.ObjectOf(astcast.ToIdent(sel.X)) == obj {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+type receiverKindChecker struct {
+	checkerBase
+
+	pointer opVariant
+	value   opVariant
+}
+
+func newReceiverKindChecker(ctxt *context) checker {
+	c := &receiverKindChecker{}
+	c.ctxt = ctxt
+	c.pointer.warning = "use pointer receiver, like other methods of this type"
+	c.value.warning = "use value receiver, like other methods of this type"
+	c.op = &operation{
+		name:     "receiver kind",
+		doc:      "pointer vs value receivers of the same type",
+		variants: []*opVariant{&c.pointer, &c.value},
+	}
+	return c
+}
+
+func (c *receiverKindChecker) Visit(n ast.Node) bool {
+	decl, ok := n.(*ast.FuncDecl)
+	if !ok || decl.Recv == nil || len(decl.Recv.List) != 1 {
+		return true
+	}
+	typ := c.ctxt.info.TypeOf(decl.Recv.List[0].Type)
+	v := &c.value
+	if ptr, ok := typ.(*types.Pointer); ok {
+		typ, v = ptr.Elem(), &c.pointer
+	}
+	named, ok := typ.(*types.Named)
+	if !ok {
+		return false
+	}
+	// Suggestions are inferred for every receiver type separately.
+	key := c.ctxt.fset.Position(named.Obj().Pos()).String()
+	c.ctxt.markScoped(decl.Recv, v, key)
+	return false
+}
+
+type fmtStringerChecker struct {
+	checkerBase
+
+	implicit opVariant
+	explicit opVariant
+}
+
+func newFmtStringerChecker(ctxt *context) checker {
+	c := &fmtStringerChecker{}
+	c.ctxt = ctxt
+	c.implicit.warning = "remove explicit String call, fmt calls it for %s and %v"
+	c.explicit.warning = "call String explicitly, like in `fmt.Sprintf(\"%s\", x.String())`"
+	c.op = &operation{
+		name:     "fmt stringer",
+		doc:      "implicit vs explicit String calls in format arguments",
+		variants: []*opVariant{&c.implicit, &c.explicit},
+		pedantic: true,
+	}
+	// Explicit String call is redundant.
+	c.op.forced = &c.implicit
+	return c
+}
+
+func (c *fmtStringerChecker) Visit(n ast.Node) bool {
+	call, ok := n.(*ast.CallExpr)
+	if !ok {
+		return true
+	}
+	var formatIndex int
+	switch pkgPath, name := qualifiedIdent(c.ctxt.info, call.Fun); {
+	case pkgPath != "fmt":
+		return true
+	case name == "Printf", name == "Sprintf", name == "Errorf":
+		// Format is the first argument.
+	case name == "Fprintf":
+		formatIndex = 1
+	default:
+		return true
+	}
+	if len(call.Args) <= formatIndex {
+		return true
+	}
+	format := c.ctxt.info.Types[call.Args[formatIndex]].Value
+	if format == nil || format.Kind() != constant.String {
+		return true
+	}
+	verbs := formatVerbs(constant.StringVal(format))
+	args := call.Args[formatIndex+1:]
+	if verbs == nil || len(verbs) != len(args) {
+		return true
+	}
+	for i, arg := range args {
+		if verbs[i] != 's' && verbs[i] != 'v' {
+			continue
+		}
+		if x := c.stringCallReceiver(arg); x != nil {
+			if c.isStringer(c.ctxt.info.TypeOf(x)) {
+				c.ctxt.mark(arg, &c.explicit)
+			}
+			continue
+		}
+		if c.isStringer(c.ctxt.info.TypeOf(arg)) {
+			c.ctxt.mark(arg, &c.implicit)
+		}
+	}
+	return true
+}
+
+// stringCallReceiver matches `x.String()` call and returns its x.
+// Returns nil for other expressions.
+func (c *fmtStringerChecker) stringCallReceiver(arg ast.Expr) ast.Expr {
+	call := astcast.ToCallExpr(arg)
+	sel := astcast.ToSelectorExpr(call.Fun)
+	if len(call.Args) != 0 || sel.Sel == nil || sel.Sel.Name != "String" {
+		return nil
+	}
+	return sel.X
+}
+
+// isStringer reports whether fmt would use the String method of
+// typ values to format them with %s and %v verbs.
+//
+// Interfaces are not included, since their dynamic types can implement
+// error or fmt.Formatter that take precedence over the String method.
+func (c *fmtStringerChecker) isStringer(typ types.Type) bool {
+	if typ == nil || types.IsInterface(typ) {
+		return false
+	}
+	methods := types.NewMethodSet(typ)
+	if methods.Lookup(nil, "Error") != nil || methods.Lookup(nil, "Format") != nil {
+		return false
+	}
+	sel := methods.Lookup(nil, "String")
+	if sel == nil {
+		return false
+	}
+	sig, ok := sel.Type().(*types.Signature)
+	return ok && sig.Params().Len() == 0 && sig.Results().Len() == 1 &&
+		types.Identical(sig.Results().At(0).Type(), types.Typ[types.String])
+}
+
+type multiAssignChecker struct {
+	checkerBase
+
+	grouped    opVariant
+	sequential opVariant
+}
+
+func newMultiAssignChecker(ctxt *context) checker {
+	c := &multiAssignChecker{}
+	c.ctxt = ctxt
+	c.grouped.warning = "group independent assignments, like in `a, b := 1, 2`"
+	c.sequential.warning = "assign one variable per statement, like in `a := 1; b := 2`"
+	c.op = &operation{
+		name:     "multi assign",
+		doc:      "grouped vs separate independent assignments",
+		variants: []*opVariant{&c.grouped, &c.sequential},
+		pedantic: true,
+	}
+	return c
+}
+
+func (c *multiAssignChecker) Visit(n ast.Node) bool {
+	if assign, ok := n.(*ast.AssignStmt); ok {
+		if len(assign.Lhs) >= 2 && c.isIndependent(assign, nil) {
+			c.ctxt.mark(n, &c.grouped)
+		}
+		return true
+	}
+
+	// Runs of 2 or more consecutive independent single assignments
+	// are reported at their first statement.
+	list := stmtList(n)
+	for i := 0; i < len(list); i++ {
+		first, ok := list[i].(*ast.AssignStmt)
+		if !ok || len(first.Lhs) != 1 || !c.isIndependent(first, nil) {
+			continue
+		}
+		run := []*ast.AssignStmt{first}
+		for _, stmt := range list[i+1:] {
+			next, ok := stmt.(*ast.AssignStmt)
+			if !ok || len(next.Lhs) != 1 || next.Tok != first.Tok || !c.isIndependent(next, run) {
+				break
+			}
+			run = append(run, next)
+		}
+		if len(run) >= 2 {
+			c.ctxt.mark(first, &c.sequential)
+		}
+		i += len(run) - 1
+	}
+	return true
+}
+
+// isIndependent reports whether assign is a `=` or `:=` assignment
+// of simple values to distinct named variables, such that no value
+// depends on the assigned variables, including the ones of prev assignments.
+// Simple values are identifiers and (possibly negated) literals.
+func (c *multiAssignChecker) isIndependent(assign *ast.AssignStmt, prev []*ast.AssignStmt) bool {
+	if assign.Tok != token.ASSIGN && assign.Tok != token.DEFINE {
+		return false
+	}
+	if len(assign.Lhs) != len(assign.Rhs) {
+		return false
+	}
+	var vars []types.Object
+	for _, p := range prev {
+		vars = append(vars, c.ctxt.info.ObjectOf(p.Lhs[0].(*ast.Ident)))
+	}
+	for _, lhs := range assign.Lhs {
+		id, ok := lhs.(*ast.Ident)
+		if !ok || id.Name == "_" {
+			return false
+		}
+		obj := c.ctxt.info.ObjectOf(id)
+		for _, v := range vars {
+			if v == obj {
+				return false
+			}
+		}
+		vars = append(vars, obj)
+	}
+	for _, rhs := range assign.Rhs {
+		if unary, ok := rhs.(*ast.UnaryExpr); ok && unary.Op == token.SUB {
+			rhs = unary.X
+		}
+		switch rhs.(type) {
+		case *ast.BasicLit, *ast.Ident:
+			// OK.
+		default:
+			return false
+		}
+		for _, v := range vars {
+			if refersTo(c.ctxt.info, rhs, v) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+type envLookupChecker struct {
+	checkerBase
+
+	lookupEnv opVariant
+	getenv    opVariant
+}
+
+func newEnvLookupChecker(ctxt *context) checker {
+	c := &envLookupChecker{}
+	c.ctxt = ctxt
+	c.lookupEnv.warning = "use os.LookupEnv to check whether the variable is set"
+	c.getenv.warning = "use os.Getenv and compare the result with \"\""
+	c.op = &operation{
+		name:     "env lookup",
+		doc:      "os.LookupEnv vs os.Getenv for checking variables",
+		variants: []*opVariant{&c.lookupEnv, &c.getenv},
+		pedantic: true,
+	}
+	// Unlike the emptiness check, os.LookupEnv distinguishes
+	// unset variables from the empty ones, so it's suggested
+	// whenever both forms are mixed.
+	c.op.preferred = &c.lookupEnv
+	return c
+}
+
+func (c *envLookupChecker) Visit(n ast.Node) bool {
+	switch n := n.(type) {
+	case *ast.CallExpr:
+		if c.isEnvCall(n, "LookupEnv") {
+			c.ctxt.mark(n, &c.lookupEnv)
+		}
+	case *ast.BinaryExpr:
+		// Match `os.Getenv(k) == ""`.
+		if call := astcast.ToCallExpr(n.X); c.isEmptyCheck(n) && c.isEnvCall(call, "Getenv") {
+			c.ctxt.mark(n, &c.getenv)
+		}
+	default:
+		// Match `v := os.Getenv(k); if v == "" {...}`.
+		list := stmtList(n)
+		for i := 1; i < len(list); i++ {
+			assign, ok := list[i-1].(*ast.AssignStmt)
+			if !ok || len(assign.Lhs) != 1 || len(assign.Rhs) != 1 {
+				continue
+			}
+			call := astcast.ToCallExpr(assign.Rhs[0])
+			ifStmt, ok := list[i].(*ast.IfStmt)
+			if !ok || ifStmt.Init != nil || !c.isEnvCall(call, "Getenv") {
+				continue
+			}
+			cond := astcast.ToBinaryExpr(ifStmt.Cond)
+			v := c.ctxt.info.ObjectOf(astcast.ToIdent(assign.Lhs[0]))
+			if v != nil && c.isEmptyCheck(cond) && c.ctxt.info.ObjectOf(astcast.ToIdent(cond.X)) == v {
+				c.ctxt.mark(call, &c.getenv)
+			}
+		}
+	}
+	return true
+}
+
+// isEnvCall reports whether call is the os package function call.
+func (c *envLookupChecker) isEnvCall(call *ast.CallExpr, name string) bool {
+	pkgPath, fn := qualifiedIdent(c.ctxt.info, call.Fun)
+	return pkgPath == "os" && fn == name && len(call.Args) == 1
+}
+
+// isEmptyCheck matches `x == ""` and `x != ""` expressions.
+func (c *envLookupChecker) isEmptyCheck(cmp *ast.BinaryExpr) bool {
+	return (cmp.Op == token.EQL || cmp.Op == token.NEQ) && astcast.ToBasicLit(cmp.Y).Value == `""`
+}
+
+// defaultNegativePrefixes is a default value of the -negative-prefixes flag.
+const defaultNegativePrefixes = "no,not,non,dis,un,disabled,without"
+
+type doubleNegationChecker struct {
+	checkerBase
+
+	plain   opVariant
+	negated opVariant
+
+	prefixes []string
+}
+
+func newDoubleNegationChecker(ctxt *context) checker {
+	c := &doubleNegationChecker{prefixes: splitPatterns(ctxt.flags.negativePrefixes)}
+	if len(c.prefixes) == 0 {
+		c.prefixes = splitPatterns(defaultNegativePrefixes)
+	}
+	c.ctxt = ctxt
+	c.plain.warning = "avoid double negation, invert the condition or rename the variable"
+	c.negated.warning = "negate negatively named booleans, like in `if !noCache {}`"
+	c.op = &operation{
+		name:     "double negation",
+		doc:      "negated negatively named booleans",
+		variants: []*opVariant{&c.plain, &c.negated},
+		pedantic: true,
+	}
+	// Double negation is always harder to read.
+	c.op.forced = &c.plain
+	return c
+}
+
+func (c *doubleNegationChecker) Visit(n ast.Node) bool {
+	switch n := n.(type) {
+	case *ast.IfStmt:
+		c.checkCond(n.Cond)
+	case *ast.ForStmt:
+		c.checkCond(n.Cond)
+	}
+	return true
+}
+
+// checkCond marks negatively named booleans of the cond operands,
+// including the operands of the && and || expressions.
+func (c *doubleNegationChecker) checkCond(cond ast.Expr) {
+	switch x := cond.(type) {
+	case *ast.ParenExpr:
+		c.checkCond(x.X)
+	case *ast.BinaryExpr:
+		if x.Op == token.LAND || x.Op == token.LOR {
+			c.checkCond(x.X)
+			c.checkCond(x.Y)
+		}
+	case *ast.UnaryExpr:
+		if x.Op == token.NOT && c.isNegativeBool(x.X) {
+			c.ctxt.mark(x, &c.negated)
+		}
+	default:
+		if c.isNegativeBool(x) {
+			c.ctxt.mark(x, &c.plain)
+		}
+	}
+}
+
+// isNegativeBool reports whether x is a boolean identifier or field
+// whose name starts with one of the negative prefix words, like noCache.
+func (c *doubleNegationChecker) isNegativeBool(x ast.Expr) bool {
+	var id *ast.Ident
+	switch x := x.(type) {
+	case *ast.Ident:
+		id = x
+	case *ast.SelectorExpr:
+		id = x.Sel
+	default:
+		return false
+	}
+	if !typep.HasBoolKind(c.ctxt.info.TypeOf(id)) {
+		return false
+	}
+	word := strings.ToLower(firstWord(id.Name))
+	for _, prefix := range c.prefixes {
+		if word == prefix {
+			return true
+		}
+	}
+	return false
+}
+
+type errReturnChecker struct {
+	checkerBase
+
+	literal  opVariant
+	variable opVariant
+}
+
+func newErrReturnChecker(ctxt *context) checker {
+	c := &errReturnChecker{}
+	c.ctxt = ctxt
+	c.literal.warning = "return zero value literals in error paths, like in `return 0, err`"
+	c.variable.warning = "return zero value variables in error paths, like in `return zero, err`"
+	c.op = &operation{
+		name:     "err return",
+		doc:      "zero value literals vs zero value variables for results in error paths",
+		variants: []*opVariant{&c.literal, &c.variable},
+		pedantic: true,
+	}
+	return c
+}
+
+func (c *errReturnChecker) Visit(n ast.Node) bool {
+	var typ *ast.FuncType
+	var body *ast.BlockStmt
+	switch n := n.(type) {
+	case *ast.FuncDecl:
+		typ, body = n.Type, n.Body
+	case *ast.FuncLit:
+		typ, body = n.Type, n.Body
+	default:
+		return true
+	}
+	if body == nil || !c.hasErrorResult(typ) {
+		return true
+	}
+
+	// Suggestions are inferred for every function separately.
+	key := c.ctxt.fset.Position(n.Pos()).String()
+	zeroVars := c.zeroVars(typ, body)
+	inspectFuncBody(body, func(n ast.Node) {
+		ifStmt, ok := n.(*ast.IfStmt)
+		if !ok || len(ifStmt.Body.List) == 0 || !isErrCheck(c.ctxt.info, ifStmt.Cond) {
+			return
+		}
+		ret, ok := ifStmt.Body.List[len(ifStmt.Body.List)-1].(*ast.ReturnStmt)
+		if !ok || len(ret.Results) < 2 {
+			return
+		}
+		for _, x := range ret.Results[:len(ret.Results)-1] {
+			switch {
+			case c.isZeroLit(x):
+				c.ctxt.markScoped(x, &c.literal, key)
+			case zeroVars[c.ctxt.info.ObjectOf(astcast.ToIdent(x))]:
+				c.ctxt.markScoped(x, &c.variable, key)
+			}
+		}
+	})
+	return true
+}
+
+// hasErrorResult reports whether the last function result is an error.
+func (c *errReturnChecker) hasErrorResult(typ *ast.FuncType) bool {
+	if typ.Results == nil || len(typ.Results.List) == 0 {
+		return false
+	}
+	last := typ.Results.List[len(typ.Results.List)-1]
+	return isErrorType(c.ctxt.info, last.Type)
+}
+
+// isZeroLit reports whether x is a zero value literal,
+// like 0, "", false, nil or `T{}`.
+func (c *errReturnChecker) isZeroLit(x ast.Expr) bool {
+	switch x := x.(type) {
+	case *ast.BasicLit:
+		v := c.ctxt.info.Types[x].Value
+		if v == nil {
+			return false
+		}
+		if v.Kind() == constant.String {
+			return constant.StringVal(v) == ""
+		}
+		return constant.Sign(v) == 0
+	case *ast.Ident:
+		obj := c.ctxt.info.ObjectOf(x)
+		return obj == types.Universe.Lookup("nil") || obj == types.Universe.Lookup("false")
+	case *ast.CompositeLit:
+		return len(x.Elts) == 0
+	default:
+		return false
+	}
+}
+
+// zeroVars returns the variables that always hold the zero value:
+// named results and `var x T` locals of the function that are never
+// assigned and whose address is never taken.
+func (c *errReturnChecker) zeroVars(typ *ast.FuncType, body *ast.BlockStmt) map[types.Object]bool {
+	vars := make(map[types.Object]bool)
+	for _, field := range typ.Results.List {
+		for _, name := range field.Names {
+			if obj := c.ctxt.info.Defs[name]; obj != nil {
+				vars[obj] = true
+			}
+		}
+	}
+	ast.Inspect(body, func(n ast.Node) bool {
+		if spec, ok := n.(*ast.ValueSpec); ok && len(spec.Values) == 0 {
+			for _, name := range spec.Names {
+				if obj, ok := c.ctxt.info.Defs[name].(*types.Var); ok {
+					vars[obj] = true
+				}
+			}
+		}
+		return true
+	})
+
+	// Closures can assign the variables too, so they're inspected as well.
+	assigned := func(x ast.Expr) {
+		if id, ok := x.(*ast.Ident); ok {
+			delete(vars, c.ctxt.info.ObjectOf(id))
+		}
+	}
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			for _, lhs := range n.Lhs {
+				assigned(lhs)
+			}
+		case *ast.IncDecStmt:
+			assigned(n.X)
+		case *ast.UnaryExpr:
+			if n.Op == token.AND {
+				assigned(n.X)
+			}
+		case *ast.RangeStmt:
+			if n.Tok == token.ASSIGN {
+				assigned(n.Key)
+				assigned(n.Value)
+			}
+		}
+		return true
+	})
+	return vars
+}
+
+type chanDirChecker struct {
+	checkerBase
+
+	directional   opVariant
+	bidirectional opVariant
+}
+
+func newChanDirChecker(ctxt *context) checker {
+	c := &chanDirChecker{}
+	c.ctxt = ctxt
+	c.directional.warning = "annotate channel direction, like in `ch <-chan T`"
+	c.bidirectional.warning = "use bidirectional channel type, like in `ch chan T`"
+	c.op = &operation{
+		name:     "chan dir",
+		doc:      "directional vs bidirectional channel parameters",
+		variants: []*opVariant{&c.directional, &c.bidirectional},
+		pedantic: true,
+	}
+	return c
+}
+
+func (c *chanDirChecker) Visit(n ast.Node) bool {
+	var typ *ast.FuncType
+	var body *ast.BlockStmt
+	switch n := n.(type) {
+	case *ast.FuncDecl:
+		typ, body = n.Type, n.Body
+	case *ast.FuncLit:
+		typ, body = n.Type, n.Body
+	default:
+		return true
+	}
+	if body == nil {
+		return true
+	}
+	for _, field := range typ.Params.List {
+		ch, ok := field.Type.(*ast.ChanType)
+		if !ok {
+			continue
+		}
+		for _, name := range field.Names {
+			if ch.Dir != ast.SEND|ast.RECV {
+				c.ctxt.mark(name, &c.directional)
+				continue
+			}
+			if obj := c.ctxt.info.ObjectOf(name); obj != nil && c.usedOneWay(body, obj) {
+				c.ctxt.mark(name, &c.bidirectional)
+			}
+		}
+	}
+	return true
+}
+
+// usedOneWay reports whether ch channel is only used for sending or
+// only for receiving inside the body, including the nested functions.
+// Channels that escape in any way, like being passed to another
+// function, are never reported.
+func (c *chanDirChecker) usedOneWay(body *ast.BlockStmt, ch types.Object) bool {
+	sends, recvs := 0, 0
+	escapes := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		id, ok := n.(*ast.Ident)
+		if !ok || c.ctxt.info.ObjectOf(id) != ch {
+			return !escapes
+		}
+		switch parent := c.ctxt.astinfo.Parents[id].(type) {
+		case *ast.SendStmt:
+			if parent.Chan == id {
+				sends++
+				return true
+			}
+		case *ast.UnaryExpr:
+			if parent.Op == token.ARROW {
+				recvs++
+				return true
+			}
+		case *ast.RangeStmt:
+			if parent.X == id {
+				recvs++
+				return true
+			}
+		case *ast.CallExpr:
+			fn := astcast.ToIdent(parent.Fun)
+			switch c.ctxt.info.ObjectOf(fn) {
+			case types.Universe.Lookup("close"):
+				sends++
+				return true
+			case types.Universe.Lookup("len"), types.Universe.Lookup("cap"):
+				return true
+			}
+		}
+		escapes = true
+		return false
+	})
+	return !escapes && (sends == 0) != (recvs == 0)
+}
+
+type redundantParensChecker struct {
+	checkerBase
+
+	bare   opVariant
+	parens opVariant
+}
+
+func newRedundantParensChecker(ctxt *context) checker {
+	c := &redundantParensChecker{}
+	c.ctxt = ctxt
+	c.bare.warning = "remove redundant parenthesis, like in `return x`"
+	c.parens.warning = "wrap operands into parenthesis, like in `return (x)`"
+	c.op = &operation{
+		name:     "redundant parens",
+		doc:      "redundant parenthesis around expressions",
+		variants: []*opVariant{&c.bare, &c.parens},
+		pedantic: true,
+	}
+	// Parenthesis around a single operand never change the meaning.
+	// Bare operands are never counted, since they're everywhere.
+	c.op.forced = &c.bare
+	return c
+}
+
+func (c *redundantParensChecker) Visit(n ast.Node) bool {
+	paren, ok := n.(*ast.ParenExpr)
+	if !ok {
+		return true
+	}
+	switch paren.X.(type) {
+	case *ast.Ident, *ast.BasicLit:
+		c.ctxt.mark(n, &c.parens)
+	}
+	return true
+}
+
+type iotaRepeatChecker struct {
+	checkerBase
+
+	implicit opVariant
+	explicit opVariant
+}
+
+func newIotaRepeatChecker(ctxt *context) checker {
+	c := &iotaRepeatChecker{}
+	c.ctxt = ctxt
+	c.implicit.warning = "omit repeated iota expression, like in `const (A Kind = iota; B)`"
+	c.explicit.warning = "repeat iota expression, like in `const (A Kind = iota; B Kind = iota)`"
+	c.op = &operation{
+		name:     "iota repeat",
+		doc:      "omitted vs repeated iota expressions in const blocks",
+		variants: []*opVariant{&c.implicit, &c.explicit},
+		pedantic: true,
+	}
+	return c
+}
+
+func (c *iotaRepeatChecker) Visit(n ast.Node) bool {
+	decl, ok := n.(*ast.GenDecl)
+	if !ok || decl.Tok != token.CONST || len(decl.Specs) < 2 {
+		return true
+	}
+	first := decl.Specs[0].(*ast.ValueSpec)
+	if len(first.Values) != 1 || !refersTo(c.ctxt.info, first.Values[0], types.Universe.Lookup("iota")) {
+		return true
+	}
+	for _, spec := range decl.Specs[1:] {
+		spec := spec.(*ast.ValueSpec)
+		switch {
+		case len(spec.Values) == 0:
+			c.ctxt.mark(spec, &c.implicit)
+		case len(spec.Values) == 1 && astequal.Expr(spec.Values[0], first.Values[0]) && c.sameType(spec, first):
+			c.ctxt.mark(spec, &c.explicit)
+		default:
+			// Implicit repetition of another expression
+			// or of another type starts here.
+			return true
+		}
+	}
+	return true
+}
+
+// sameType reports whether x and y specs have the same explicit type or no type.
+// Implicit repetition keeps the type, so `B = iota` is not the same as
+// omitted B expression after `A Kind = iota`.
+func (c *iotaRepeatChecker) sameType(x, y *ast.ValueSpec) bool {
+	if x.Type == nil || y.Type == nil {
+		return x.Type == nil && y.Type == nil
+	}
+	return astequal.Expr(x.Type, y.Type)
+}
+
+type testFailChecker struct {
+	checkerBase
+
+	errorCall opVariant
+	fatalCall opVariant
+}
+
+func newTestFailChecker(ctxt *context) checker {
+	c := &testFailChecker{}
+	c.ctxt = ctxt
+	c.errorCall.warning = "report failed checks with t.Error, like in `t.Errorf(format, got, want)`"
+	c.fatalCall.warning = "report failed checks with t.Fatal, like in `t.Fatalf(format, got, want)`"
+	c.op = &operation{
+		name:     "test fail",
+		doc:      "t.Error vs t.Fatal for failed checks in tests",
+		variants: []*opVariant{&c.errorCall, &c.fatalCall},
+		pedantic: true,
+	}
+	return c
+}
+
+func (c *testFailChecker) Visit(n ast.Node) bool {
+	if _, ok := n.(ast.Decl); ok {
+		// Only the test files are checked.
+		return strings.HasSuffix(c.ctxt.fset.File(n.Pos()).Name(), "_test.go")
+	}
+	// Only the failed check reporting, like in `if got != want { t.Errorf(...) }`.
+	ifStmt, ok := n.(*ast.IfStmt)
+	if !ok || ifStmt.Else != nil || len(ifStmt.Body.List) != 1 {
+		return true
+	}
+	stmt, ok := ifStmt.Body.List[0].(*ast.ExprStmt)
+	if !ok {
+		return true
+	}
+	call, ok := stmt.X.(*ast.CallExpr)
+	if !ok {
+		return true
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || !c.isTestingT(sel.X) {
+		return true
+	}
+	switch sel.Sel.Name {
+	case "Error", "Errorf":
+		c.ctxt.mark(call, &c.errorCall)
+	case "Fatal", "Fatalf":
+		c.ctxt.mark(call, &c.fatalCall)
+	}
+	return true
+}
+
+// isTestingT reports whether x is a `t` identifier of the *testing.T type.
+func (c *testFailChecker) isTestingT(x ast.Expr) bool {
+	id, ok := x.(*ast.Ident)
+	if !ok || id.Name != "t" {
+		return false
+	}
+	return isTestingPtr(c.ctxt.info.TypeOf(id), "T")
+}
+
+type structTagChecker struct {
+	checkerBase
+
+	tagged   opVariant
+	untagged opVariant
+}
+
+func newStructTagChecker(ctxt *context) checker {
+	c := &structTagChecker{}
+	c.ctxt = ctxt
+	c.tagged.warning = "add a field tag, like the most of the struct fields have"
+	c.untagged.warning = "omit field tags"
+	c.op = &operation{
+		name:     "struct tags",
+		doc:      "tagged vs untagged exported struct fields",
+		variants: []*opVariant{&c.tagged, &c.untagged},
+		pedantic: true,
+	}
+	return c
+}
+
+func (c *structTagChecker) Visit(n ast.Node) bool {
+	typ, ok := n.(*ast.StructType)
+	if !ok {
+		return true
+	}
+	// Embedded and unexported fields are usually not (de)serialized.
+	var fields []*ast.Field
+	tagged := 0
+	for _, field := range typ.Fields.List {
+		if len(field.Names) == 0 || !field.Names[0].IsExported() {
+			continue
+		}
+		fields = append(fields, field)
+		if field.Tag != nil {
+			tagged++
+		}
+	}
+	// Only the untagged fields in the structs with mostly tagged fields
+	// are reported, so the untagged variant is never suggested.
+	if tagged*2 <= len(fields) {
+		return true
+	}
+	key := c.ctxt.fset.Position(typ.Pos()).String()
+	for _, field := range fields {
+		if field.Tag != nil {
+			c.ctxt.markScoped(field, &c.tagged, key)
+		} else {
+			c.ctxt.markScoped(field, &c.untagged, key)
+		}
+	}
+	return true
+}
+
+type dotImportChecker struct {
+	checkerBase
+
+	qualified opVariant
+	dot       opVariant
+}
+
+func newDotImportChecker(ctxt *context) checker {
+	c := &dotImportChecker{}
+	c.ctxt = ctxt
+	c.qualified.warning = "avoid dot import, qualify the package identifiers (advisory, needs manual changes)"
+	c.dot.warning = "use dot import, like in `import . \"pkg\"`"
+	c.op = &operation{
+		name:     "dot import",
+		doc:      "dot imports vs qualified identifiers",
+		variants: []*opVariant{&c.qualified, &c.dot},
+		pedantic: true,
+	}
+	// Dot imports make it unclear where the identifiers come from.
+	// Qualified imports are never counted, since they're everywhere.
+	c.op.forced = &c.qualified
+	return c
+}
+
+func (c *dotImportChecker) Visit(n ast.Node) bool {
+	spec, ok := n.(*ast.ImportSpec)
+	if !ok {
+		return true
+	}
+	if spec.Name != nil && spec.Name.Name == "." {
+		c.ctxt.mark(n, &c.dot)
+	}
+	return false
+}
+
+type stdImportGroupChecker struct {
+	checkerBase
+
+	separate opVariant
+	mixed    opVariant
+}
+
+func newStdImportGroupChecker(ctxt *context) checker {
+	c := &stdImportGroupChecker{}
+	c.ctxt = ctxt
+	c.separate.warning = "separate standard library imports from other imports with an empty line"
+	c.mixed.warning = "don't separate standard library imports from other imports"
+	c.op = &operation{
+		name:     "std import group",
+		doc:      "separate vs mixed standard library import groups",
+		variants: []*opVariant{&c.separate, &c.mixed},
+		pedantic: true,
+	}
+	return c
+}
+
+func (c *stdImportGroupChecker) Visit(n ast.Node) bool {
+	decl, ok := n.(*ast.GenDecl)
+	if !ok || decl.Tok != token.IMPORT || len(decl.Specs) < 2 {
+		return false
+	}
+
+	// Import specs are grouped by the empty lines between them,
+	// like in the blank import check.
+	// Blank imports are handled by the blank import check.
+	hasStd, hasOther := false, false
+	groupStd, groupOther := false, false
+	mixed := false
+	prevLine := 0
+	for _, spec := range decl.Specs {
+		spec := spec.(*ast.ImportSpec)
+		start := spec.Pos()
+		if spec.Doc != nil {
+			start = spec.Doc.Pos()
+		}
+		line := c.ctxt.fset.Position(start).Line
+		if prevLine != 0 && line > prevLine+1 {
+			groupStd, groupOther = false, false
+		}
+		prevLine = c.ctxt.fset.Position(spec.End()).Line
+
+		path, err := strconv.Unquote(spec.Path.Value)
+		if err != nil || (spec.Name != nil && spec.Name.Name == "_") {
+			continue
+		}
+		if isStdImportPath(path) {
+			groupStd, hasStd = true, true
+		} else {
+			groupOther, hasOther = true, true
+		}
+		if groupStd && groupOther {
+			mixed = true
+		}
+	}
+	switch {
+	case !hasStd || !hasOther:
+		// Nothing to separate.
+	case mixed:
+		c.ctxt.mark(decl, &c.mixed)
+	default:
+		c.ctxt.mark(decl, &c.separate)
+	}
+	return false
+}
+
+// isStdImportPath reports whether path looks like a standard library
+// package path: its first element doesn't contain a dot, like in "net/http".
+// This is the same heuristic goimports uses.
+func isStdImportPath(path string) bool {
+	first := path
+	if slash := strings.IndexByte(path, '/'); slash != -1 {
+		first = path[:slash]
+	}
+	return !strings.Contains(first, ".")
+}
+
+type structVerbChecker struct {
+	checkerBase
+
+	plain  opVariant
+	fields opVariant
+	syntax opVariant
+}
+
+func newStructVerbChecker(ctxt *context) checker {
+	c := &structVerbChecker{}
+	c.ctxt = ctxt
+	c.plain.warning = "format structs with %v, like other format calls do"
+	c.fields.warning = "format structs with %+v, like other format calls do"
+	c.syntax.warning = "format structs with %#v, like other format calls do"
+	c.op = &operation{
+		name:     "struct verb",
+		doc:      "%v vs %+v vs %#v for formatting structs",
+		variants: []*opVariant{&c.plain, &c.fields, &c.syntax},
+		pedantic: true,
+	}
+	// Structs are usually formatted for the debug output,
+	// the conventions tend to differ between the packages.
+	c.op.scope = "package"
+	return c
+}
+
+func (c *structVerbChecker) Visit(n ast.Node) bool {
+	call, ok := n.(*ast.CallExpr)
+	if !ok {
+		return true
+	}
+	var formatIndex int
+	switch pkgPath, name := qualifiedIdent(c.ctxt.info, call.Fun); {
+	case pkgPath == "fmt" && (name == "Printf" || name == "Sprintf" || name == "Errorf"):
+		// Format is the first argument.
+	case pkgPath == "fmt" && name == "Fprintf":
+		formatIndex = 1
+	case pkgPath == "log" && (name == "Printf" || name == "Fatalf" || name == "Panicf"):
+		// Format is the first argument.
+	default:
+		return true
+	}
+	if len(call.Args) <= formatIndex {
+		return true
+	}
+	format := c.ctxt.info.Types[call.Args[formatIndex]].Value
+	if format == nil || format.Kind() != constant.String {
+		return true
+	}
+	directives := formatDirectives(constant.StringVal(format))
+	args := call.Args[formatIndex+1:]
+	if directives == nil || len(directives) != len(args) {
+		return true
+	}
+	for i, arg := range args {
+		if !c.isStruct(c.ctxt.info.TypeOf(arg)) {
+			continue
+		}
+		switch directives[i] {
+		case "v":
+			c.ctxt.mark(arg, &c.plain)
+		case "+v":
+			c.ctxt.mark(arg, &c.fields)
+		case "#v":
+			c.ctxt.mark(arg, &c.syntax)
+		}
+	}
+	return true
+}
+
+// isStruct reports whether typ is a struct or a pointer to struct.
+// Structs with String or Error methods are not formatted field by field.
+func (c *structVerbChecker) isStruct(typ types.Type) bool {
+	if typ == nil {
+		return false
+	}
+	for _, name := range []string{"String", "Error"} {
+		if obj, _, _ := types.LookupFieldOrMethod(typ, true, nil, name); obj != nil {
+			return false
+		}
+	}
+	if ptr, ok := typ.Underlying().(*types.Pointer); ok {
+		typ = ptr.Elem()
+	}
+	_, ok := typ.Underlying().(*types.Struct)
+	return ok
+}
+
+type zeroFieldChecker struct {
+	checkerBase
+
+	omitted  opVariant
+	explicit opVariant
+}
+
+func newZeroFieldChecker(ctxt *context) checker {
+	c := &zeroFieldChecker{}
+	c.ctxt = ctxt
+	c.omitted.warning = "omit zero value fields, like in `T{Name: name}`"
+	c.explicit.warning = "set zero value fields explicitly, like in `T{Name: name, Count: 0}`"
+	c.op = &operation{
+		name:     "zero field",
+		doc:      "omitted vs explicit zero value fields in composite literals",
+		variants: []*opVariant{&c.omitted, &c.explicit},
+		pedantic: true,
+	}
+	// Omitted fields are zero-initialized anyway.
+	// They're never counted, since there is nothing to match.
+	c.op.forced = &c.omitted
+	return c
+}
+
+func (c *zeroFieldChecker) Visit(n ast.Node) bool {
+	lit, ok := n.(*ast.CompositeLit)
+	if !ok {
+		return true
+	}
+	typ := c.ctxt.info.TypeOf(lit)
+	if ptr, ok := typ.(*types.Pointer); ok {
+		// Elided &T in the nested literals, like in `[]*T{{X: 0}}`.
+		typ = ptr.Elem()
+	}
+	if typ == nil {
+		return true
+	}
+	st, ok := typ.Underlying().(*types.Struct)
+	if !ok {
+		return true
+	}
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			return true // Not a keyed literal
+		}
+		key, ok := kv.Key.(*ast.Ident)
+		if !ok {
+			continue
+		}
+		for i := 0; i < st.NumFields(); i++ {
+			if field := st.Field(i); field.Name() == key.Name && c.isZeroLit(kv.Value, field.Type()) {
+				c.ctxt.mark(kv, &c.explicit)
+			}
+		}
+	}
+	return true
+}
+
+// isZeroLit reports whether x is a zero value literal of the typ:
+// 0, "", false or nil.
+// Other zero values, like `T{}`, are not matched.
+func (c *zeroFieldChecker) isZeroLit(x ast.Expr, typ types.Type) bool {
+	basic, ok := typ.Underlying().(*types.Basic)
+	switch x := x.(type) {
+	case *ast.BasicLit:
+		switch {
+		case !ok:
+			return false
+		case basic.Info()&types.IsNumeric != 0:
+			return (x.Kind == token.INT || x.Kind == token.FLOAT) && normalizedValueOf(x) == "0"
+		case basic.Info()&types.IsString != 0:
+			return x.Kind == token.STRING && (x.Value == `""` || x.Value == "``")
+		}
+	case *ast.Ident:
+		obj := c.ctxt.info.ObjectOf(x)
+		switch {
+		case obj == types.Universe.Lookup("false"):
+			return ok && basic.Info()&types.IsBoolean != 0
+		case obj == types.Universe.Lookup("nil"):
+			// Nil interface is a zero value too.
+			return true
+		}
+	}
+	return false
+}
+
+type errorStringChecker struct {
+	checkerBase
+
+	conventional opVariant
+	sentence     opVariant
+}
+
+func newErrorStringChecker(ctxt *context) checker {
+	c := &errorStringChecker{}
+	c.ctxt = ctxt
+	c.conventional.warning = "use lower case error strings without trailing punctuation, like in `errors.New(\"not found\")`"
+	c.sentence.warning = "use capitalized error strings with trailing punctuation, like in `errors.New(\"Not found.\")`"
+	c.op = &operation{
+		name:     "error string",
+		doc:      "error strings capitalization and trailing punctuation",
+		variants: []*opVariant{&c.conventional, &c.sentence},
+	}
+	// Error strings are usually wrapped into other messages,
+	// see https://go.dev/wiki/CodeReviewComments#error-strings.
+	c.op.forced = &c.conventional
+	return c
+}
+
+func (c *errorStringChecker) Visit(n ast.Node) bool {
+	call, ok := n.(*ast.CallExpr)
+	if !ok || len(call.Args) == 0 {
+		return true
+	}
+	switch pkgPath, name := qualifiedIdent(c.ctxt.info, call.Fun); {
+	case pkgPath == "errors" && name == "New":
+	case pkgPath == "fmt" && name == "Errorf":
+	default:
+		return true
+	}
+	lit, ok := call.Args[0].(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return true
+	}
+	s, err := strconv.Unquote(valueOf(lit))
+	if err != nil || s == "" {
+		return true
+	}
+	if c.isCapitalized(s) || strings.ContainsAny(s[len(s)-1:], ".!?:\n") {
+		c.ctxt.mark(lit, &c.sentence)
+	} else {
+		c.ctxt.mark(lit, &c.conventional)
+	}
+	return true
+}
+
+// isCapitalized reports whether s starts with an upper case letter.
+// Words that look like acronyms and identifiers, like "HTTP" or
+// "Config.Load", are not considered capitalized.
+func (c *errorStringChecker) isCapitalized(s string) bool {
+	word := s
+	if space := strings.IndexByte(s, ' '); space != -1 {
+		word = s[:space]
+	}
+	first, size := utf8.DecodeRuneInString(word)
+	if !unicode.IsUpper(first) {
+		return false
+	}
+	for _, ch := range word[size:] {
+		if !unicode.IsLower(ch) {
+			return false
+		}
+	}
+	return true
+}
+
+type testHelperChecker struct {
+	checkerBase
+
+	helper   opVariant
+	noHelper opVariant
+}
+
+func newTestHelperChecker(ctxt *context) checker {
+	c := &testHelperChecker{}
+	c.ctxt = ctxt
+	c.helper.warning = "call t.Helper() in test helpers, like other helpers do"
+	c.noHelper.warning = "don't call t.Helper() in test helpers, like other helpers do"
+	c.op = &operation{
+		name:     "test helper",
+		doc:      "t.Helper calls in test helpers",
+		variants: []*opVariant{&c.helper, &c.noHelper},
+		pedantic: true,
+	}
+	// Test helpers are usually package-specific.
+	c.op.scope = "package"
+	return c
+}
+
+func (c *testHelperChecker) Visit(n ast.Node) bool {
+	// Only the top-level functions are checked.
+	decl, ok := n.(*ast.FuncDecl)
+	if !ok || decl.Body == nil || !strings.HasSuffix(c.ctxt.fset.File(n.Pos()).Name(), "_test.go") {
+		return false
+	}
+	for _, prefix := range []string{"Test", "Benchmark", "Fuzz", "Example"} {
+		if strings.HasPrefix(decl.Name.Name, prefix) {
+			return false
+		}
+	}
+	params := decl.Type.Params.List
+	if len(params) == 0 || len(params[0].Names) == 0 {
+		return false
+	}
+	t := c.ctxt.info.Defs[params[0].Names[0]]
+	if t == nil || !(isTestingPtr(t.Type(), "T") || isTestingPtr(t.Type(), "B")) {
+		return false
+	}
+
+	// Test helpers are the functions that report failures.
+	reports, helper := false, false
+	inspectFuncBody(decl.Body, func(n ast.Node) {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || !refersTo(c.ctxt.info, sel.X, t) {
+			return
+		}
+		switch sel.Sel.Name {
+		case "Error", "Errorf", "Fatal", "Fatalf":
+			reports = true
+		case "Helper":
+			helper = true
+		}
+	})
+	switch {
+	case !reports:
+		// Not a test helper.
+	case helper:
+		c.ctxt.mark(decl.Name, &c.helper)
+	default:
+		c.ctxt.mark(decl.Name, &c.noHelper)
+	}
+	return false
+}
+
+type constBaseChecker struct {
+	checkerBase
+
+	decimal opVariant
+	hex     opVariant
+	octal   opVariant
+	binary  opVariant
+}
+
+func newConstBaseChecker(ctxt *context) checker {
+	c := &constBaseChecker{}
+	c.ctxt = ctxt
+	c.decimal.warning = "use decimal literals, like other constants of this block"
+	c.hex.warning = "use hex literals, like other constants of this block"
+	c.octal.warning = "use octal literals, like other constants of this block"
+	c.binary.warning = "use binary literals, like other constants of this block"
+	c.op = &operation{
+		name:     "const base",
+		doc:      "literal bases of the constants in a const block",
+		variants: []*opVariant{&c.decimal, &c.hex, &c.octal, &c.binary},
+		pedantic: true,
+	}
+	return c
+}
+
+func (c *constBaseChecker) Visit(n ast.Node) bool {
+	decl, ok := n.(*ast.GenDecl)
+	if !ok || decl.Tok != token.CONST || len(decl.Specs) < 2 {
+		return true
+	}
+	// Suggestions are inferred for every const block separately.
+	key := c.ctxt.fset.Position(decl.Pos()).String()
+	for _, spec := range decl.Specs {
+		spec := spec.(*ast.ValueSpec)
+		if len(spec.Values) != 1 {
+			continue
+		}
+		lit, ok := spec.Values[0].(*ast.BasicLit)
+		// Zero looks the same in every base.
+		if !ok || lit.Kind != token.INT || normalizedValueOf(lit) == "0" {
+			continue
+		}
+		c.ctxt.markScoped(spec, c.baseOf(valueOf(lit)), key)
+	}
+	return true
+}
+
+// baseOf returns the op variant of the integer literal base.
+func (c *constBaseChecker) baseOf(value string) *opVariant {
+	switch {
+	case strings.HasPrefix(value, "0x"), strings.HasPrefix(value, "0X"):
+		return &c.hex
+	case strings.HasPrefix(value, "0b"), strings.HasPrefix(value, "0B"):
+		return &c.binary
+	case strings.HasPrefix(value, "0") && len(value) > 1:
+		// Both 0o17 and legacy 017 forms.
+		return &c.octal
+	default:
+		return &c.decimal
+	}
+}
+
+type ctxParamChecker struct {
+	checkerBase
+
+	first    opVariant
+	notFirst opVariant
+}
+
+func newCtxParamChecker(ctxt *context) checker {
+	c := &ctxParamChecker{}
+	c.ctxt = ctxt
+	c.first.warning = "make context.Context the first parameter, like in `f(ctx context.Context, x T)`"
+	c.notFirst.warning = "don't make context.Context the first parameter, like in `f(x T, ctx context.Context)`"
+	c.op = &operation{
+		name:     "ctx param",
+		doc:      "context.Context parameter position",
+		variants: []*opVariant{&c.first, &c.notFirst},
+		pedantic: true,
+	}
+	// This is the convention of the standard library and the context package docs.
+	c.op.forced = &c.first
+	return c
+}
+
+func (c *ctxParamChecker) Visit(n ast.Node) bool {
+	decl, ok := n.(*ast.FuncDecl)
+	if !ok {
+		return false
+	}
+	i := 0
+	for _, field := range decl.Type.Params.List {
+		if c.isContext(c.ctxt.info.TypeOf(field.Type)) {
+			if i == 0 {
+				c.ctxt.mark(decl.Name, &c.first)
+			} else {
+				c.ctxt.mark(decl.Name, &c.notFirst)
+			}
+			return false
+		}
+		if len(field.Names) == 0 {
+			i++
+		} else {
+			i += len(field.Names)
+		}
+	}
+	return false
+}
+
+// isContext reports whether typ is context.Context.
+func (c *ctxParamChecker) isContext(typ types.Type) bool {
+	named, ok := typ.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return false
+	}
+	return named.Obj().Pkg().Path() == "context" && named.Obj().Name() == "Context"
+}
+
+type sliceBoundsChecker struct {
+	checkerBase
+
+	implicit opVariant
+	explicit opVariant
+}
+
+func newSliceBoundsChecker(ctxt *context) checker {
+	c := &sliceBoundsChecker{}
+	c.ctxt = ctxt
+	c.implicit.warning = "omit default slice bounds, like in `s[:]` and `s[i:]`"
+	c.explicit.warning = "write default slice bounds explicitly, like in `s[0:len(s)]`"
+	c.op = &operation{
+		name:     "slice bounds",
+		doc:      "implicit vs explicit default slice bounds",
+		variants: []*opVariant{&c.implicit, &c.explicit},
+		pedantic: true,
+	}
+	// Default bounds are noise, they can also get out of sync with the sliced expression.
+	// Implicit bounds are never counted, since they're everywhere.
+	c.op.forced = &c.implicit
+	return c
+}
+
+func (c *sliceBoundsChecker) Visit(n ast.Node) bool {
+	e, ok := n.(*ast.SliceExpr)
+	if !ok {
+		return true
+	}
+	lowDefault := e.Low != nil && c.isZero(e.Low)
+	// High bound is required in 3-index slices.
+	highDefault := e.High != nil && !e.Slice3 && c.isLenOf(e.High, e.X)
+	if lowDefault || highDefault {
+		c.ctxt.mark(e, &c.explicit)
+	}
+	return true
+}
+
+func (c *sliceBoundsChecker) isZero(x ast.Expr) bool {
+	lit := astcast.ToBasicLit(x)
+	return lit.Kind == token.INT && sameLitValue(lit, "0")
+}
+
+// isLenOf matches `len(s)` expression.
+//
+// Expressions with calls are skipped, since `f()[:len(f())]`
+// is not the same as `f()[:]`.
+func (c *sliceBoundsChecker) isLenOf(x, s ast.Expr) bool {
+	call := astcast.ToCallExpr(x)
+	if len(call.Args) != 1 || !isBuiltin(c.ctxt.info, call.Fun, "len") || !astequal.Expr(call.Args[0], s) {
+		return false
+	}
+	hasCalls := false
+	ast.Inspect(s, func(n ast.Node) bool {
+		if _, ok := n.(*ast.CallExpr); ok {
+			hasCalls = true
+		}
+		return !hasCalls
 	})
+	return !hasCalls
 }
 
-type operation struct {
-	// name is a human-readable operation descriptor.
-	//
-	// Initialized by checker constructor.
-	name string
+type goroutineArgsChecker struct {
+	checkerBase
 
-	// suggested is an op variant that is inferred as the most frequently used one.
-	//
-	// Updated during the context.assignSuggestions.
-	suggested *opVariant
+	args    opVariant
+	capture opVariant
 
-	// variants is a list of equivalent operation forms.
-	//
-	// Initialized by checker constructor.
-	variants []*opVariant
+	// loopVars are the variables declared by the for and range
+	// statements of the file.
+	loopVars map[types.Object]bool
+	file     *ast.File
 }
 
-type opVariant struct {
-	// id is an globally-unique operation variant ID.
-	//
-	// Initialized by context.initCheckers.
-	id int
+func newGoroutineArgsChecker(ctxt *context) checker {
+	c := &goroutineArgsChecker{}
+	c.ctxt = ctxt
+	c.args.warning = "pass variables to goroutines as arguments, like in `go func(x T) {...}(x)`"
+	c.capture.warning = "capture variables in goroutines, like in `go func() {...}()`"
+	c.op = &operation{
+		name:     "goroutine args",
+		doc:      "explicit arguments vs captured variables in goroutines",
+		variants: []*opVariant{&c.args, &c.capture},
+		pedantic: true,
+	}
+	return c
+}
 
-	// op is a reference to a containing operation.
-	//
-	// Initialized by context.initCheckers.
-	op *operation
+func (c *goroutineArgsChecker) Visit(n ast.Node) bool {
+	if f, ok := c.ctxt.astinfo.Origin.(*ast.File); ok && f != c.file {
+		c.file = f
+		c.loopVars = make(map[types.Object]bool)
+	}
+	switch n := n.(type) {
+	case *ast.RangeStmt:
+		if n.Tok == token.DEFINE {
+			c.addLoopVars(n.Key, n.Value)
+		}
+	case *ast.ForStmt:
+		if init, ok := n.Init.(*ast.AssignStmt); ok && init.Tok == token.DEFINE {
+			c.addLoopVars(init.Lhs...)
+		}
+	case *ast.GoStmt:
+		lit, ok := n.Call.Fun.(*ast.FuncLit)
+		if !ok {
+			return true
+		}
+		if len(n.Call.Args) != 0 {
+			c.ctxt.mark(n, &c.args)
+			return true
+		}
+		captured := c.capturedVars(lit)
+		if len(captured) == 0 {
+			return true // Nothing to pass
+		}
+		c.ctxt.mark(n, &c.capture)
+		if c.hasPerIterationVars() {
+			return true
+		}
+		for _, id := range captured {
+			if c.loopVars[c.ctxt.info.ObjectOf(id)] {
+				c.ctxt.addNote(n, "goroutine captures the "+id.Name+" loop variable by reference")
+				break
+			}
+		}
+	}
+	return true
+}
 
-	// warning is a message to use if this variant is not used
-	// when it is the suggested one.
-	//
-	// For example, if the variant requires the usage of the
-	// parenthesis, warning should suggest adding them.
-	//
-	// Initialized by checker constructor.
-	warning string
+// hasPerIterationVars reports whether the file language version is go1.22
+// or later, where every loop iteration has its own loop variables.
+// Unknown versions are treated as the older ones.
+func (c *goroutineArgsChecker) hasPerIterationVars() bool {
+	v := c.ctxt.info.FileVersions[c.file]
+	return v != "" && goversion.Compare(v, "go1.22") >= 0
+}
 
-	// count is a counter for op variant usages.
-	//
-	// Updated during the context.collectCandidates.
-	count int
+func (c *goroutineArgsChecker) addLoopVars(list ...ast.Expr) {
+	for _, x := range list {
+		if id, ok := x.(*ast.Ident); ok && id.Name != "_" {
+			if obj := c.ctxt.info.ObjectOf(id); obj != nil {
+				c.loopVars[obj] = true
+			}
+		}
+	}
 }
 
-type checker interface {
-	Visit(n ast.Node) bool
-	Operation() *operation
+// capturedVars returns the first reference to every local variable
+// that is declared outside of the lit function.
+func (c *goroutineArgsChecker) capturedVars(lit *ast.FuncLit) []*ast.Ident {
+	var captured []*ast.Ident
+	seen := make(map[types.Object]bool)
+	ast.Inspect(lit.Body, func(n ast.Node) bool {
+		id, ok := n.(*ast.Ident)
+		if !ok {
+			return true
+		}
+		v, ok := c.ctxt.info.Uses[id].(*types.Var)
+		if !ok || v.IsField() || v.Pkg() == nil || v.Parent() == v.Pkg().Scope() {
+			return true
+		}
+		if v.Pos() >= lit.Pos() && v.Pos() < lit.End() {
+			return true // Declared inside the lit
+		}
+		if !seen[v] {
+			seen[v] = true
+			captured = append(captured, id)
+		}
+		return true
+	})
+	return captured
 }
 
-type checkerBase struct {
-	ctxt *context
-	op   *operation
+type deferFuncChecker struct {
+	checkerBase
+
+	named opVariant
+	lit   opVariant
 }
 
-func (c *checkerBase) Operation() *operation {
-	return c.op
+func newDeferFuncChecker(ctxt *context) checker {
+	c := &deferFuncChecker{}
+	c.ctxt = ctxt
+	c.named.warning = "defer named function calls, like in `defer cleanup()`"
+	c.lit.warning = "defer function literals, like in `defer func() {...}()`"
+	c.op = &operation{
+		name:     "defer func",
+		doc:      "deferred named function calls vs function literals",
+		variants: []*opVariant{&c.named, &c.lit},
+		pedantic: true,
+	}
+	return c
 }
 
-type candidate struct {
-	variantID  int
-	locationID int
+func (c *deferFuncChecker) Visit(n ast.Node) bool {
+	var body *ast.BlockStmt
+	switch n := n.(type) {
+	case *ast.FuncDecl:
+		body = n.Body
+	case *ast.FuncLit:
+		body = n.Body
+	default:
+		return true
+	}
+	if body == nil {
+		return true
+	}
+
+	// Suggestions are inferred for every function separately.
+	key := c.ctxt.fset.Position(n.Pos()).String()
+	inspectFuncBody(body, func(n ast.Node) {
+		stmt, ok := n.(*ast.DeferStmt)
+		if !ok {
+			return
+		}
+		switch stmt.Call.Fun.(type) {
+		case *ast.FuncLit:
+			c.ctxt.markScoped(stmt, &c.lit, key)
+		case *ast.Ident, *ast.SelectorExpr:
+			c.ctxt.markScoped(stmt, &c.named, key)
+		}
+	})
+	return true
+}
+
+// defaultLoggers is a default value of the -loggers flag.
+const defaultLoggers = "log/slog,go.uber.org/zap,github.com/rs/zerolog"
+
+type loggerChecker struct {
+	checkerBase
+
+	structured opVariant
+	std        opVariant
+
+	loggers map[string]bool
+}
+
+func newLoggerChecker(ctxt *context) checker {
+	c := &loggerChecker{loggers: make(map[string]bool)}
+	paths := splitPatterns(ctxt.flags.loggers)
+	if len(paths) == 0 {
+		paths = splitPatterns(defaultLoggers)
+	}
+	for _, path := range paths {
+		c.loggers[path] = true
+	}
+	c.ctxt = ctxt
+	c.structured.warning = "use the structured logger instead of the log package"
+	c.std.warning = "use the log package instead of the structured logger"
+	c.op = &operation{
+		name:     "logger",
+		doc:      "structured loggers vs the log package",
+		variants: []*opVariant{&c.structured, &c.std},
+		pedantic: true,
+	}
+	return c
+}
+
+func (c *loggerChecker) Visit(n ast.Node) bool {
+	call, ok := n.(*ast.CallExpr)
+	if !ok {
+		return true
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return true
+	}
+	fn, ok := c.ctxt.info.ObjectOf(sel.Sel).(*types.Func)
+	if !ok || fn.Pkg() == nil {
+		return true
+	}
+	// Both package functions and methods, like in `log.Printf()` and `logger.Printf()`.
+	path := fn.Pkg().Path()
+	switch {
+	case path == "log" && c.hasPrefix(fn.Name(), "Print", "Fatal", "Panic"):
+		c.ctxt.mark(call, &c.std)
+	case c.loggers[path] && loggerLevelFuncs[fn.Name()] && (path == "log/slog" || c.isLoggerMethod(fn)):
+		c.ctxt.mark(call, &c.structured)
+	}
+	return true
+}
+
+// loggerLevelFuncs are the logging functions and methods of the structured loggers.
+//
+// Package functions of the loggers, except for the slog ones,
+// are usually field or option constructors, like `zap.Error(err)`.
+var loggerLevelFuncs = map[string]bool{
+	"Debug": true, "Debugf": true, "Debugw": true, "Debugln": true, "DebugContext": true,
+	"Info": true, "Infof": true, "Infow": true, "Infoln": true, "InfoContext": true,
+	"Warn": true, "Warnf": true, "Warnw": true, "Warnln": true, "WarnContext": true,
+	"Error": true, "Errorf": true, "Errorw": true, "Errorln": true, "ErrorContext": true,
+	"DPanic": true, "DPanicf": true, "DPanicw": true, "DPanicln": true,
+	"Panic": true, "Panicf": true, "Panicw": true, "Panicln": true,
+	"Fatal": true, "Fatalf": true, "Fatalw": true, "Fatalln": true,
+	"Log": true, "LogAttrs": true,
+}
+
+// isLoggerMethod reports whether fn is a method of the logger type,
+// like zap.Logger, zap.SugaredLogger or slog.Logger.
+func (c *loggerChecker) isLoggerMethod(fn *types.Func) bool {
+	recv := fn.Type().(*types.Signature).Recv()
+	if recv == nil {
+		return false
+	}
+	typ := recv.Type()
+	if ptr, ok := typ.(*types.Pointer); ok {
+		typ = ptr.Elem()
+	}
+	named, ok := typ.(*types.Named)
+	return ok && strings.HasSuffix(named.Obj().Name(), "Logger")
+}
+
+func (c *loggerChecker) hasPrefix(name string, prefixes ...string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+type slogMsgChecker struct {
+	checkerBase
+
+	attrs   opVariant
+	sprintf opVariant
+}
+
+func newSlogMsgChecker(ctxt *context) checker {
+	c := &slogMsgChecker{}
+	c.ctxt = ctxt
+	c.attrs.warning = "pass values as key-value attributes, like in `slog.Info(\"msg\", \"key\", v)`"
+	c.sprintf.warning = "format the message, like in `slog.Info(fmt.Sprintf(\"msg %v\", v))`"
+	c.op = &operation{
+		name:     "slog msg",
+		doc:      "key-value attributes vs formatted slog messages",
+		variants: []*opVariant{&c.attrs, &c.sprintf},
+		pedantic: true,
+	}
+	// Formatted messages defeat the structured logging.
+	// Attributes are never counted, since they're not always needed.
+	c.op.forced = &c.attrs
+	return c
+}
+
+func (c *slogMsgChecker) Visit(n ast.Node) bool {
+	call, ok := n.(*ast.CallExpr)
+	if !ok {
+		return true
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return true
+	}
+	fn, ok := c.ctxt.info.ObjectOf(sel.Sel).(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Pkg().Path() != "log/slog" {
+		return true
+	}
+	i := c.msgIndex(fn.Name())
+	if i < 0 || i >= len(call.Args) {
+		return true
+	}
+	if path, name := qualifiedIdent(c.ctxt.info, astcast.ToCallExpr(call.Args[i]).Fun); path == "fmt" && name == "Sprintf" {
+		c.ctxt.mark(call.Args[i], &c.sprintf)
+	}
+	return true
+}
+
+// msgIndex returns the message argument index of the slog function
+// or method call, or -1 if it's not a logging call.
+func (c *slogMsgChecker) msgIndex(name string) int {
+	switch name {
+	case "Debug", "Info", "Warn", "Error":
+		return 0
+	case "DebugContext", "InfoContext", "WarnContext", "ErrorContext":
+		return 1 // After ctx
+	case "Log", "LogAttrs":
+		return 2 // After ctx and level
+	default:
+		return -1
+	}
+}
+
+// smallStructSize is the max size of the struct types in bytes
+// that are checked by the ctor result check, 4 words on 64-bit platforms.
+const smallStructSize = 32
+
+type ctorResultChecker struct {
+	checkerBase
+
+	value opVariant
+	ptr   opVariant
+
+	sizes types.Sizes
+}
+
+func newCtorResultChecker(ctxt *context) checker {
+	c := &ctorResultChecker{sizes: &types.StdSizes{WordSize: 8, MaxAlign: 8}}
+	c.ctxt = ctxt
+	c.value.warning = "return small structs by value from constructors, like other constructors do"
+	c.ptr.warning = "return small structs by pointer from constructors, like other constructors do"
+	c.op = &operation{
+		name:     "ctor result",
+		doc:      "value vs pointer results of small struct constructors",
+		variants: []*opVariant{&c.value, &c.ptr},
+		pedantic: true,
+	}
+	// Constructors are rare, every package has only a few of them.
+	c.op.scope = "global"
+	return c
+}
+
+func (c *ctorResultChecker) Visit(n ast.Node) bool {
+	decl, ok := n.(*ast.FuncDecl)
+	if !ok {
+		return false
+	}
+	if decl.Recv != nil || !c.isCtorName(decl.Name.Name) {
+		return false
+	}
+	results := decl.Type.Results
+	if results == nil || len(results.List) == 0 {
+		return false
+	}
+	typ := c.ctxt.info.TypeOf(results.List[0].Type)
+	v := &c.value
+	if ptr, ok := typ.(*types.Pointer); ok {
+		typ, v = ptr.Elem(), &c.ptr
+	}
+	if c.isSmallStruct(typ, c.ctxt.info.ObjectOf(decl.Name).Pkg()) {
+		c.ctxt.mark(decl.Name, v)
+	}
+	return false
+}
+
+// isCtorName reports whether name is New or starts with New word, like NewReader.
+func (c *ctorResultChecker) isCtorName(name string) bool {
+	if !strings.HasPrefix(name, "New") {
+		return false
+	}
+	rest := name[len("New"):]
+	return rest == "" || unicode.IsUpper([]rune(rest)[0])
+}
+
+// isSmallStruct reports whether typ is a named struct type of the pkg package
+// and its size doesn't exceed smallStructSize.
+func (c *ctorResultChecker) isSmallStruct(typ types.Type, pkg *types.Package) bool {
+	named, ok := typ.(*types.Named)
+	if !ok || named.Obj().Pkg() != pkg {
+		return false
+	}
+	if _, ok := named.Underlying().(*types.Struct); !ok {
+		return false
+	}
+	return c.sizes.Sizeof(named) <= smallStructSize
 }
 
 type defaultCaseOrderChecker struct {
@@ -97,6 +3960,7 @@ func newDefaultCaseOrderChecker(ctxt *context) checker {
 	c.last.warning = "default case should be the last case"
 	c.op = &operation{
 		name:     "default case order",
+		doc:      "first vs last default case in switch statements",
 		variants: []*opVariant{&c.first, &c.last},
 	}
 	return c
@@ -153,6 +4017,7 @@ func newNonZeroLenTestChecker(ctxt *context) checker {
 	c.gte1.warning = "use `len(s) >= 1`"
 	c.op = &operation{
 		name:     "non-zero length test",
+		doc:      "len(s) != 0 vs len(s) > 0 vs len(s) >= 1",
 		variants: []*opVariant{&c.neq0, &c.gt0, &c.gte1},
 	}
 	return c
@@ -193,6 +4058,7 @@ func newZeroValPtrAllocChecker(ctxt *context) checker {
 	c.addressOfLit.warning = "use &T{} for *T allocation"
 	c.op = &operation{
 		name:     "zero value ptr alloc",
+		doc:      "new(T) vs &T{} for zero value pointers",
 		variants: []*opVariant{&c.newCall, &c.addressOfLit},
 	}
 	return c
@@ -238,6 +4104,7 @@ func newHexLitChecker(ctxt *context) checker {
 	c.upperCase.warning = "use A-F (upper case) digits"
 	c.op = &operation{
 		name:     "hex lit",
+		doc:      "lower case vs upper case hex digits",
 		variants: []*opVariant{&c.lowerCase, &c.upperCase},
 	}
 	return c
@@ -251,11 +4118,16 @@ func (c *hexLitChecker) Visit(n ast.Node) bool {
 	if lit.Kind != token.INT || !strings.HasPrefix(lit.Value, "0x") {
 		return false
 	}
+	digits := lit.Value[len("0x"):]
 	switch {
-	case strings.ContainsAny(lit.Value, "abcdef"):
-		c.ctxt.mark(n, &c.lowerCase)
-	case strings.ContainsAny(lit.Value, "ABCDEF"):
-		c.ctxt.mark(n, &c.upperCase)
+	case strings.ContainsAny(digits, "abcdef"):
+		if sameLitValue(lit, "0x"+strings.ToUpper(digits)) {
+			c.ctxt.mark(n, &c.lowerCase)
+		}
+	case strings.ContainsAny(digits, "ABCDEF"):
+		if sameLitValue(lit, "0x"+strings.ToLower(digits)) {
+			c.ctxt.mark(n, &c.upperCase)
+		}
 	}
 	return false
 }
@@ -274,6 +4146,7 @@ func newRangeCheckChecker(ctxt *context) checker {
 	c.alignCenter.warning = "use align-center, like in `low < x && x < high`"
 	c.op = &operation{
 		name:     "range check",
+		doc:      "align-left vs align-center range checks",
 		variants: []*opVariant{&c.alignLeft, &c.alignCenter},
 	}
 	return c
@@ -320,6 +4193,7 @@ func newAndNotChecker(ctxt *context) checker {
 	c.withSpace.warning = "put a space between & and ^, like in `x & ^y`"
 	c.op = &operation{
 		name:     "and-not",
+		doc:      "x &^ y vs x & ^y spelling",
 		variants: []*opVariant{&c.noSpace, &c.withSpace},
 	}
 	return c
@@ -350,6 +4224,7 @@ func newFloatLitChecker(ctxt *context) checker {
 	c.implicitIntFrac.warning = "use implicit int/frac part, like in `1.` and `.1`"
 	c.op = &operation{
 		name:     "float lit",
+		doc:      "explicit vs implicit int and frac parts of float literals",
 		variants: []*opVariant{&c.explicitIntFrac, &c.implicitIntFrac},
 	}
 	return c
@@ -363,12 +4238,25 @@ func (c *floatLitChecker) Visit(n ast.Node) bool {
 	if lit.Kind != token.FLOAT {
 		return false
 	}
+	// alt is the same literal spelled in the other variant.
+	// Literals that can't be spelled the other way, like `1e5`, are not counted.
+	var v *opVariant
+	alt := ""
 	integer, frac := c.splitIntFrac(lit)
 	switch {
-	case (integer == "0" && frac != "") || (integer != "" && frac == "0"):
-		c.ctxt.mark(n, &c.explicitIntFrac)
-	case (integer != "" && frac == "") || (integer == "" && frac != ""):
-		c.ctxt.mark(n, &c.implicitIntFrac)
+	case integer == "0" && frac != "":
+		v, alt = &c.explicitIntFrac, "."+frac
+	case integer != "" && frac == "0":
+		v, alt = &c.explicitIntFrac, integer+"."
+	case integer != "" && frac == "":
+		v, alt = &c.implicitIntFrac, integer+".0"
+	case integer == "" && frac != "":
+		v, alt = &c.implicitIntFrac, "0."+frac
+	default:
+		return false
+	}
+	if sameLitValue(lit, alt) {
+		c.ctxt.mark(n, v)
 	}
 	return false
 }
@@ -404,6 +4292,7 @@ func newLabelCaseChecker(ctxt *context) checker {
 	c.lowerCamelCaseRE = regexp.MustCompile(`^[a-z]\w*$`)
 	c.op = &operation{
 		name: "label case",
+		doc:  "ALL_UPPER vs UpperCamelCase vs lowerCamelCase labels",
 		variants: []*opVariant{
 			&c.allUpperCase,
 			&c.upperCamelCase,
@@ -443,6 +4332,7 @@ func newUntypedConstCoerceChecker(ctxt *context) checker {
 	c.rhsType.warning = "specity type in RHS, like in `var x = T(const)`"
 	c.op = &operation{
 		name:     "untyped const coerce",
+		doc:      "typed declarations vs conversions of untyped constants",
 		variants: []*opVariant{&c.lhsType, &c.rhsType},
 	}
 	return c
@@ -515,6 +4405,7 @@ func newEmptyMapChecker(ctxt *context) checker {
 	c.mapLit.warning = "use map[K]V{}"
 	c.op = &operation{
 		name:     "empty map",
+		doc:      "make vs literal for empty maps",
 		variants: []*opVariant{&c.makeCall, &c.mapLit},
 	}
 	return c
@@ -567,6 +4458,7 @@ func newEmptySliceChecker(ctxt *context) checker {
 	c.sliceLit.warning = "use []T{}"
 	c.op = &operation{
 		name:     "empty slice",
+		doc:      "make vs literal for empty slices",
 		variants: []*opVariant{&c.makeCall, &c.sliceLit},
 	}
 	return c
@@ -619,6 +4511,7 @@ func newArgListParensChecker(ctxt *context) checker {
 	c.nextLine.warning = "move `)` to the next line and put `,` after the last argument"
 	c.op = &operation{
 		name:     "arg list parens",
+		doc:      "closing parenthesis placement in multi-line calls",
 		variants: []*opVariant{&c.sameLine, &c.nextLine},
 	}
 	return c
@@ -660,6 +4553,7 @@ func newUnitImportChecker(ctxt *context) checker {
 	c.withParens.warning = "wrap single-package import spec into parenthesis"
 	c.op = &operation{
 		name:     "unit import",
+		doc:      "plain vs parenthesized single-package imports",
 		variants: []*opVariant{&c.noParens, &c.withParens},
 	}
 	return c
@@ -691,6 +4585,7 @@ func newSameTypeArgumentsChecker(ctxt *context) checker {
 	c.optionalTypesOmited.warning = "use only one type declaration after several arguments of the same type"
 	c.op = &operation{
 		name:     "same type arguments",
+		doc:      "repeated vs grouped types of the same type parameters",
 		variants: []*opVariant{&c.allTypesPresent, &c.optionalTypesOmited},
 	}
 	return c
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestOperationSuggest(t *testing.T) {
+	tests := []struct {
+		counts    []int
+		forced    int
+		preferred int
+		suggested int
+		undecided bool
+	}{
+		{[]int{0, 0}, -1, -1, 0, false},
+		{[]int{1, 0}, -1, -1, 0, false},
+		{[]int{2, 3}, -1, -1, 1, false},
+		{[]int{3, 3}, -1, -1, 0, true},
+		{[]int{1, 2, 2}, -1, -1, 1, true},
+		{[]int{2, 1, 2}, -1, -1, 0, true},
+		{[]int{1, 3, 2}, -1, -1, 1, false},
+		{[]int{3, 3}, 1, -1, 1, false},
+
+		{[]int{5, 1}, -1, 1, 1, false},
+		{[]int{3, 3}, -1, 1, 1, false},
+		{[]int{5, 0}, -1, 1, 0, false},
+		{[]int{1, 5}, 0, 1, 0, false},
+	}
+
+	for _, test := range tests {
+		op := &operation{}
+		for i, count := range test.counts {
+			op.variants = append(op.variants, &opVariant{id: i, count: count})
+		}
+		if test.forced != -1 {
+			op.forced = op.variants[test.forced]
+		}
+		if test.preferred != -1 {
+			op.preferred = op.variants[test.preferred]
+		}
+		suggested, undecided := op.suggest(func(v *opVariant) int { return v.count })
+		if suggested.id != test.suggested || undecided != test.undecided {
+			t.Errorf("counts=%v forced=%d preferred=%d: have (%d, %v), want (%d, %v)",
+				test.counts, test.forced, test.preferred,
+				suggested.id, undecided, test.suggested, test.undecided)
+		}
+	}
+}
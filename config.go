@@ -0,0 +1,172 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"text/template"
+)
+
+// configFilename is a name of the config file that is
+// discovered automatically, see findConfig.
+const configFilename = ".go-consistent.yml"
+
+// config is a go-consistent configuration file contents.
+//
+// Config files are YAML documents, see loadConfig.
+type config struct {
+	// Operations maps operation names to their settings.
+	Operations map[string]*opConfig `json:"operations"`
+}
+
+// opConfig holds the settings of a single operation.
+type opConfig struct {
+	// Scope overrides the -scope flag value for the operation.
+	Scope string `json:"scope"`
+
+	// Message is a text/template for the operation warnings,
+	// see warningData for the available fields.
+	Message string `json:"message"`
+}
+
+// warningData is passed to the opConfig.Message templates.
+type warningData struct {
+	// Operation is an operation name, like "empty map".
+	Operation string
+
+	// Suggested is a suggested variant warning, like "use make(map[K]V)".
+	Suggested string
+
+	// Actual is a warning of the variant that is used, like "use map[K]V{}".
+	Actual string
+
+	// Variant is a suggested variant letter, like in -force flag.
+	Variant string
+}
+
+// loadConfig reads the config from the YAML file, see parseYAML.
+// JSON files are accepted too, since JSON is a subset of YAML.
+// Unknown fields are reported as errors to catch the typos early.
+func loadConfig(filename string) (*config, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	if trimmed := bytes.TrimSpace(data); len(trimmed) == 0 || trimmed[0] != '{' {
+		doc, err := parseYAML(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", filename, err)
+		}
+		// Re-encode the document, so it's decoded with the same rules.
+		if data, err = json.Marshal(doc); err != nil {
+			return nil, fmt.Errorf("%s: %v", filename, err)
+		}
+	}
+	var conf config
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&conf); err != nil {
+		return nil, fmt.Errorf("%s: %v", filename, err)
+	}
+	return &conf, nil
+}
+
+// findConfig returns the path to the closest configFilename file,
+// starting from the dir and walking up to the filesystem root.
+// Returns empty string if there is no such file.
+func findConfig(dir string) string {
+	for {
+		filename := filepath.Join(dir, configFilename)
+		if info, err := os.Stat(filename); err == nil && !info.IsDir() {
+			return filename
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// applyConfig assigns operation settings from the ctxt.config.
+func (ctxt *context) applyConfig(checkers []checker) error {
+	if ctxt.config == nil {
+		return nil
+	}
+
+	ops := make(map[string]*operation, len(checkers))
+	for _, c := range checkers {
+		op := c.Operation()
+		ops[op.name] = op
+	}
+
+	for name, opConf := range ctxt.config.Operations {
+		op := ops[name]
+		if op == nil {
+			return fmt.Errorf("%q: unknown operation", name)
+		}
+		if opConf == nil {
+			continue
+		}
+		switch opConf.Scope {
+		case "":
+			// Keep the default scope.
+		case "global", "module", "package":
+			op.scope = opConf.Scope
+		default:
+			return fmt.Errorf("%q: unexpected scope %q", name, opConf.Scope)
+		}
+		if opConf.Message != "" {
+			tmpl, err := parseMessageTemplate(name, opConf.Message)
+			if err != nil {
+				return fmt.Errorf("%q: message: %v", name, err)
+			}
+			op.message = tmpl
+		}
+	}
+
+	return nil
+}
+
+// parseMessageTemplate parses the opConfig.Message template.
+// The template is executed once, so references to the unknown
+// fields are reported before the warnings are printed.
+func parseMessageTemplate(name, text string) (*template.Template, error) {
+	tmpl, err := template.New(name).Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	if err := tmpl.Execute(ioutil.Discard, warningData{}); err != nil {
+		return nil, err
+	}
+	return tmpl, nil
+}
+
+// warningText returns the warning message for the v variant usage
+// when the suggested variant is expected: the operation name and
+// the suggestion, unless the operation has a message template.
+func warningText(v, suggested *opVariant) string {
+	op := suggested.op
+	if op.message == nil {
+		return op.name + ": " + suggested.warning
+	}
+	data := warningData{
+		Operation: op.name,
+		Suggested: suggested.warning,
+		Actual:    v.warning,
+	}
+	for i, variant := range op.variants {
+		if variant == suggested {
+			data.Variant = variantLetter(i)
+		}
+	}
+	var buf bytes.Buffer
+	if err := op.message.Execute(&buf, data); err != nil {
+		// Templates are validated by the parseMessageTemplate.
+		return op.name + ": " + suggested.warning
+	}
+	return buf.String()
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeTestConfig(t *testing.T, data string) string {
+	dir, err := ioutil.TempDir("", "go-consistent")
+	if err != nil {
+		t.Fatalf("create temp dir: %v", err)
+	}
+	filename := filepath.Join(dir, "config.yml")
+	if err := ioutil.WriteFile(filename, []byte(data), 0644); err != nil {
+		os.RemoveAll(dir)
+		t.Fatalf("write config: %v", err)
+	}
+	return filename
+}
+
+func TestLoadConfig(t *testing.T) {
+	tests := []struct {
+		data string
+		good bool
+	}{
+		{`{}`, true},
+		{`{"operations": {"hex lit": {"scope": "package"}}}`, true},
+		{`{"operations": {"hex lit": null}}`, true},
+		{"", true},
+		{"operations:\n  hex lit:\n    scope: package\n", true},
+		{"# Comment.\noperations:\n  hex lit:\n", true},
+		{"operations:\n  hex lit:\n    message: \"{{.Operation}}: prefer {{.Suggested}}\"\n", true},
+
+		{`{"operations": {"hex lit": {"scopes": "package"}}}`, false},
+		{`{"operations": []}`, false},
+		{`{`, false},
+		{"operations:\n  hex lit:\n    scopes: package\n", false},
+		{"operations:\n  - hex lit\n", false},
+		{"operations: []\n", false},
+		{"operations:\n  hex lit: package\n", false},
+	}
+
+	for _, test := range tests {
+		filename := writeTestConfig(t, test.data)
+		_, err := loadConfig(filename)
+		os.RemoveAll(filepath.Dir(filename))
+		if test.good && err != nil {
+			t.Errorf("%s: unexpected error: %v", test.data, err)
+		}
+		if !test.good && err == nil {
+			t.Errorf("%s: expected an error", test.data)
+		}
+	}
+}
+
+func TestFindConfig(t *testing.T) {
+	root, err := ioutil.TempDir("", "go-consistent")
+	if err != nil {
+		t.Fatalf("create temp dir: %v", err)
+	}
+	defer os.RemoveAll(root)
+
+	nested := filepath.Join(root, "a", "b", "c")
+	if err := os.MkdirAll(nested, 0755); err != nil {
+		t.Fatalf("create dirs: %v", err)
+	}
+	if have := findConfig(nested); strings.HasPrefix(have, root) {
+		t.Errorf("expected no config, found %s", have)
+	}
+
+	rootConfig := filepath.Join(root, configFilename)
+	if err := ioutil.WriteFile(rootConfig, []byte(`{}`), 0644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	if have := findConfig(nested); have != rootConfig {
+		t.Errorf("findConfig mismatch: have %q, want %q", have, rootConfig)
+	}
+
+	// The closest config wins.
+	nestedConfig := filepath.Join(root, "a", configFilename)
+	if err := ioutil.WriteFile(nestedConfig, []byte(`{}`), 0644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	if have := findConfig(nested); have != nestedConfig {
+		t.Errorf("findConfig mismatch: have %q, want %q", have, nestedConfig)
+	}
+	if have := findConfig(root); have != rootConfig {
+		t.Errorf("findConfig mismatch: have %q, want %q", have, rootConfig)
+	}
+}
+
+func TestBadConfig(t *testing.T) {
+	tests := []*config{
+		{Operations: map[string]*opConfig{"unknown": {}}},
+		{Operations: map[string]*opConfig{"hex lit": {Scope: "file"}}},
+		{Operations: map[string]*opConfig{"hex lit": {Message: "{{.Operation"}}},
+		{Operations: map[string]*opConfig{"hex lit": {Message: "{{.Unknown}}"}}},
+	}
+
+	for _, conf := range tests {
+		ctxt := newTestContext(t)
+		ctxt.config = conf
+		if err := ctxt.initCheckers(); err == nil {
+			t.Errorf("%+v: expected an error", conf.Operations)
+		}
+	}
+}
+
+func TestOperationScope(t *testing.T) {
+	paths := []string{
+		"./" + path.Join("testdata", "multimodule", "a"),
+		"./" + path.Join("testdata", "multimodule", "nested", "b"),
+	}
+	tests := []struct {
+		flagScope string
+		opScope   string
+		warnings  int
+	}{
+		{"global", "", 1},
+		{"global", "module", 0},
+		{"global", "package", 0},
+		{"package", "global", 1},
+		{"module", "", 0},
+	}
+
+	for _, test := range tests {
+		ctxt := newTestContext(t)
+		ctxt.paths = paths
+		ctxt.flags.scope = test.flagScope
+		ctxt.config = &config{
+			Operations: map[string]*opConfig{
+				"hex lit": {Scope: test.opScope},
+			},
+		}
+		runAnalysis(t, ctxt)
+		warnings := 0
+		visitWarings(ctxt, func(pos token.Position, v, suggested *opVariant) {
+			warnings++
+		})
+		if warnings != test.warnings {
+			t.Errorf("%s/%s: warnings mismatch: have %d, want %d",
+				test.flagScope, test.opScope, warnings, test.warnings)
+		}
+	}
+}
+
+func TestMessageTemplate(t *testing.T) {
+	ctxt := newTestContext(t)
+	ctxt.config = &config{
+		Operations: map[string]*opConfig{
+			"hex lit": {Message: "{{.Operation}}: prefer {{.Suggested}} over {{.Actual}} ({{.Variant}})"},
+		},
+	}
+	if err := ctxt.initCheckers(); err != nil {
+		t.Fatalf("init checkers: %v", err)
+	}
+	for _, c := range ctxt.checkers {
+		op := c.Operation()
+		v, suggested := op.variants[0], op.variants[1]
+		var want string
+		switch op.name {
+		case "hex lit":
+			want = "hex lit: prefer use A-F (upper case) digits over use a-f (lower case) digits (B)"
+		case "empty map":
+			want = "empty map: use map[K]V{}"
+		default:
+			continue
+		}
+		if have := warningText(v, suggested); have != want {
+			t.Errorf("%s: warning text mismatch:\nhave: %s\nwant: %s", op.name, have, want)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// loadChangedLines reads the -include-only-changed-lines patch, if it's set.
+// The "-" patch filename stands for the standard input.
+//
+// Patch paths are relative to the -diff-root directory, like the git diff
+// paths are relative to the repository root, see diffRoot.
+func (ctxt *context) loadChangedLines() error {
+	filename := ctxt.flags.changedLines
+	if filename == "" {
+		return nil
+	}
+	var r io.Reader = os.Stdin
+	if filename != "-" {
+		f, err := os.Open(filename)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+	changed, err := parseUnifiedDiff(r)
+	if err != nil {
+		return fmt.Errorf("%s: %v", filename, err)
+	}
+	root := ctxt.diffRoot()
+	ctxt.changedLines = make(map[string]map[int]bool, len(changed))
+	for path, lines := range changed {
+		path = filepath.FromSlash(path)
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(root, path)
+		}
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			return err
+		}
+		ctxt.changedLines[abs] = lines
+	}
+	return nil
+}
+
+// diffRoot returns the directory the patch paths are relative to:
+// the -diff-root flag value or the git repository root of the
+// current directory. Outside of the git repositories, paths are
+// relative to the current directory.
+func (ctxt *context) diffRoot() string {
+	if ctxt.flags.diffRoot != "" {
+		return ctxt.flags.diffRoot
+	}
+	root, err := gitTopLevel(".")
+	if err != nil {
+		ctxt.infoPrintf("resolve diff paths against the current directory: %v", err)
+		return "."
+	}
+	return root
+}
+
+// gitTopLevel returns the root directory of the git repository
+// that contains the dir directory.
+func gitTopLevel(dir string) (string, error) {
+	cmd := exec.Command("git", "rev-parse", "--show-toplevel")
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git rev-parse: %v", err)
+	}
+	return filepath.FromSlash(strings.TrimSpace(string(out))), nil
+}
+
+// isChangedLine reports whether the warning at filename:line
+// should be reported according to the -include-only-changed-lines patch.
+func (ctxt *context) isChangedLine(filename string, line int) bool {
+	return ctxt.changedLines == nil || ctxt.changedLines[filename][line]
+}
+
+// parseUnifiedDiff returns the added and changed lines of every
+// new file version of the unified diff, like the `git diff` output.
+// File paths are returned without the "b/" prefix of git diffs.
+func parseUnifiedDiff(r io.Reader) (map[string]map[int]bool, error) {
+	changed := make(map[string]map[int]bool)
+	var lines map[int]bool
+	line := 0      // Current line number in the new file version
+	remaining := 0 // Number of new file lines left in the current hunk
+
+	s := bufio.NewScanner(r)
+	s.Buffer(nil, 1024*1024)
+	for s.Scan() {
+		text := s.Text()
+		switch {
+		case remaining == 0 && strings.HasPrefix(text, "+++ "):
+			path := strings.TrimPrefix(text, "+++ ")
+			if tab := strings.IndexByte(path, '\t'); tab != -1 {
+				path = path[:tab] // Strip the timestamp
+			}
+			lines = nil
+			if path == "/dev/null" {
+				continue // File is deleted
+			}
+			path = strings.TrimPrefix(path, "b/")
+			lines = changed[path]
+			if lines == nil {
+				lines = make(map[int]bool)
+				changed[path] = lines
+			}
+		case remaining == 0 && strings.HasPrefix(text, "@@ "):
+			start, count, err := parseHunkHeader(text)
+			if err != nil {
+				return nil, err
+			}
+			line, remaining = start, count
+		case remaining == 0:
+			// File headers and other lines between the hunks.
+		case strings.HasPrefix(text, "+"):
+			if lines != nil {
+				lines[line] = true
+			}
+			line++
+			remaining--
+		case strings.HasPrefix(text, "-"), strings.HasPrefix(text, `\`):
+			// Removed line or "\ No newline at end of file".
+		default:
+			// Context line.
+			line++
+			remaining--
+		}
+	}
+	return changed, s.Err()
+}
+
+// parseHunkHeader returns the new file range of the hunk header,
+// like 10 and 3 for `@@ -8,2 +10,3 @@ func f() {`.
+func parseHunkHeader(text string) (start, count int, err error) {
+	fields := strings.Fields(text)
+	if len(fields) < 3 || !strings.HasPrefix(fields[2], "+") {
+		return 0, 0, fmt.Errorf("bad hunk header %q", text)
+	}
+	lineRange := strings.TrimPrefix(fields[2], "+")
+	count = 1
+	if comma := strings.IndexByte(lineRange, ','); comma != -1 {
+		count, err = strconv.Atoi(lineRange[comma+1:])
+		if err != nil {
+			return 0, 0, fmt.Errorf("bad hunk header %q", text)
+		}
+		lineRange = lineRange[:comma]
+	}
+	start, err = strconv.Atoi(lineRange)
+	if err != nil {
+		return 0, 0, fmt.Errorf("bad hunk header %q", text)
+	}
+	return start, count, nil
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseUnifiedDiff(t *testing.T) {
+	patch := `diff --git a/foo.go b/foo.go
+index 1111111..2222222 100644
+--- a/foo.go
++++ b/foo.go
+@@ -1,4 +1,5 @@
+ package foo
+-var a = 1
++var a = 2
++var b = 3
+ 
+ func f() {}
+@@ -10 +11,0 @@ func g() {
+-	removed()
+@@ -20,2 +20,2 @@
+--- line that starts with dashes
+++++ line that starts with pluses
+ context
+\ No newline at end of file
+diff --git a/old.go b/old.go
+deleted file mode 100644
+--- a/old.go
++++ /dev/null
+@@ -1 +0,0 @@
+-package old
+--- /dev/null
++++ b/dir/new.go
+@@ -0,0 +1,2 @@
++package dir
++
+`
+	have, err := parseUnifiedDiff(strings.NewReader(patch))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	want := map[string]map[int]bool{
+		"foo.go":     {2: true, 3: true, 20: true},
+		"dir/new.go": {1: true, 2: true},
+	}
+	if !reflect.DeepEqual(have, want) {
+		t.Errorf("changed lines mismatch:\nhave: %v\nwant: %v", have, want)
+	}
+
+	if _, err := parseUnifiedDiff(strings.NewReader("+++ b/x.go\n@@ bad @@\n")); err == nil {
+		t.Errorf("expected an error for malformed hunk header")
+	}
+}
+
+func TestChangedLines(t *testing.T) {
+	patch := writeTestConfig(t, `--- a/testdata/filter/a.go
++++ b/testdata/filter/a.go
+@@ -4,0 +5 @@
++	_ = map[string]int{}
+--- a/testdata/filter/b.go
++++ b/testdata/filter/b.go
+@@ -1,3 +1,3 @@
+ package filter
+ 
+-func makeCall() {
++func makeCall() {
+`)
+	ctxt := newTestContext(t)
+	ctxt.paths = []string{"./" + path.Join("testdata", "filter")}
+	ctxt.flags.changedLines = patch
+	if err := ctxt.loadChangedLines(); err != nil {
+		t.Fatalf("load changed lines: %v", err)
+	}
+	runAnalysis(t, ctxt)
+	if err := ctxt.printWarnings(); err != nil {
+		t.Fatalf("print warnings: %v", err)
+	}
+	// The only warning is for the b.go:4 line that is not changed.
+	if ctxt.warnings != 0 {
+		t.Errorf("expected warnings outside of changed lines to be ignored, got %d", ctxt.warnings)
+	}
+
+	ctxt.changedLines = nil
+	ctxt.warnings = 0
+	if err := ctxt.printWarnings(); err != nil {
+		t.Fatalf("print warnings: %v", err)
+	}
+	if ctxt.warnings != 1 {
+		t.Errorf("expected 1 warning without the patch, got %d", ctxt.warnings)
+	}
+}
+
+func TestDiffRoot(t *testing.T) {
+	want, err := filepath.Abs(filepath.Join("testdata", "filter", "b.go"))
+	if err != nil {
+		t.Fatalf("abs path: %v", err)
+	}
+	tests := []struct {
+		diffRoot string
+		path     string
+	}{
+		// The repository root is the current directory.
+		{"", "testdata/filter/b.go"},
+		{".", "testdata/filter/b.go"},
+		{"testdata", "filter/b.go"},
+		{filepath.Dir(filepath.Dir(want)), "filter/b.go"},
+		{"testdata", want},
+	}
+
+	for _, test := range tests {
+		patch := writeTestConfig(t, "+++ b/"+test.path+"\n@@ -4 +4 @@\n+\t_ = map[string]int{}\n")
+		ctxt := newTestContext(t)
+		ctxt.flags.changedLines = patch
+		ctxt.flags.diffRoot = test.diffRoot
+		err := ctxt.loadChangedLines()
+		os.RemoveAll(filepath.Dir(patch))
+		if err != nil {
+			t.Fatalf("%q: load changed lines: %v", test.diffRoot, err)
+		}
+		if !ctxt.isChangedLine(want, 4) {
+			t.Errorf("%q/%q: expected %s:4 to be changed, have %v",
+				test.diffRoot, test.path, want, ctxt.changedLines)
+		}
+	}
+}
+
+func TestGitTopLevel(t *testing.T) {
+	root, err := gitTopLevel(".")
+	if err != nil {
+		t.Skipf("not a git repository: %v", err)
+	}
+	have, err := gitTopLevel(filepath.Join("testdata", "filter"))
+	if err != nil {
+		t.Fatalf("git top level: %v", err)
+	}
+	if have != root {
+		t.Errorf("top level mismatch: have %q, want %q", have, root)
+	}
+}
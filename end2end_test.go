@@ -1,8 +1,17 @@
 package main
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
 	"go/token"
+	"io/ioutil"
+	"log"
+	"os"
 	"path"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/Quasilyte/go-consistent/internal/end2end"
@@ -17,48 +26,752 @@ func TestEnd2End(t *testing.T) {
 		"negative_tests2.go",
 		"negative_tests3.go",
 		"negative_tests4.go",
+		"pedantic_positive_tests1.go",
+		"pedantic_positive_tests2.go",
+		"pedantic_positive_tests3.go",
+		"pedantic_negative_tests1.go",
+		"pedantic_negative_tests2.go",
+		"pedantic_negative_tests3.go",
+		"want_tests.go",
 	}
 
 	for _, filename := range filenames {
+		filename := filename
 		t.Run(filename, func(t *testing.T) {
-			rel := path.Join("testdata", filename)
-			f, err := end2end.ParseTestFile(rel)
-			if err != nil {
-				t.Fatalf("parse %s: %v", rel, err)
-			}
+			// Every subtest uses its own context, so they can run in parallel.
+			t.Parallel()
+			checkTestFile(t, path.Join("testdata", filename), strings.HasPrefix(filename, "pedantic_"))
+		})
+	}
+}
+
+// checkTestFile runs the inference and reporting for the filename
+// and checks the warnings against its matcher comments:
+// `//= text` and `//~ regexp` comments for the next line and
+// analysistest-style `// want "regexp"` comments for their own line.
+func checkTestFile(t *testing.T, filename string, pedantic bool) {
+	f, err := end2end.ParseTestFile(filename)
+	if err != nil {
+		t.Fatalf("parse %s: %v", filename, err)
+	}
 
-			var ctxt context
-			ctxt.paths = []string{rel}
-			ctxt.initCheckers()
-			if err := ctxt.collectAllCandidates(); err != nil {
-				t.Fatalf("collect candidates: %v", err)
+	ctxt := newTestContext(t)
+	ctxt.paths = []string{filename}
+	ctxt.flags.pedantic = pedantic
+	runAnalysis(t, ctxt)
+	visitWarings(ctxt, func(pos token.Position, v, suggested *opVariant) {
+		text := v.op.name + ": " + suggested.warning
+		mlist, ok := f.Matchers[pos.Line]
+		if !ok {
+			t.Errorf("%s: unexpected warning: %s", pos, text)
+			return
+		}
+
+		for _, m := range mlist {
+			if m.Match(text) {
+				m.Matches++
+				break
+			} else {
+				t.Errorf("%s: unexpected warning: %s", m.Position(), text)
 			}
-			ctxt.assignSuggestions()
-			visitWarings(&ctxt, func(pos token.Position, v *opVariant) {
-				text := v.op.name + ": " + v.op.suggested.warning
-				mlist, ok := f.Matchers[pos.Line]
-				if !ok {
-					t.Errorf("%s: unexpected warning: %s", pos, text)
-					return
-				}
-
-				for _, m := range mlist {
-					if m.Match(text) {
-						m.Matches++
-						break
-					} else {
-						t.Errorf("%s: unexpected warning: %s", m.Position(), text)
-					}
-				}
-			})
-
-			for _, mlist := range f.Matchers {
-				for _, m := range mlist {
-					if !m.IsMatched() {
-						t.Errorf("%s: no matches: %s", m.Position(), m.Text())
-					}
-				}
+		}
+	})
+
+	for _, mlist := range f.Matchers {
+		for _, m := range mlist {
+			if !m.IsMatched() {
+				t.Errorf("%s: no matches: %s", m.Position(), m.Text())
 			}
+		}
+	}
+}
+
+func TestTestFiles(t *testing.T) {
+	// Test-only checks must ignore the non-test files of the package.
+	checkTestFile(t, path.Join("testdata", "testfail", "a_test.go"), true)
+	checkTestFile(t, path.Join("testdata", "testhelper", "a_test.go"), true)
+}
+
+func TestCollectErrors(t *testing.T) {
+	ctxt := newTestContext(t)
+	ctxt.paths = []string{path.Join("testdata", "bad", "syntax_error.go")}
+	if err := ctxt.initCheckers(); err != nil {
+		t.Fatalf("init checkers: %v", err)
+	}
+	if err := ctxt.collectAllCandidates(); err == nil {
+		t.Errorf("expected an error for unparseable file")
+	}
+}
+
+// testLogWriter redirects context diagnostics into the test log.
+type testLogWriter struct {
+	t *testing.T
+}
+
+func (w testLogWriter) Write(p []byte) (int, error) {
+	w.t.Log(string(p))
+	return len(p), nil
+}
+
+func newTestContext(t *testing.T) *context {
+	return &context{
+		logger: log.New(testLogWriter{t: t}, "", 0),
+		out:    ioutil.Discard,
+	}
+}
+
+// runAnalysis initializes the ctxt checkers, collects the candidates
+// and assigns the suggestions, so the warnings can be visited.
+func runAnalysis(t *testing.T, ctxt *context) {
+	t.Helper()
+	steps := []runStep{
+		{"init checkers", ctxt.initCheckers},
+		{"collect candidates", ctxt.collectAllCandidates},
+		{"assign suggestions", ctxt.assignSuggestions},
+	}
+	for _, step := range steps {
+		if err := step.fn(); err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+	}
+}
+
+func TestNoInference(t *testing.T) {
+	ctxt := newTestContext(t)
+	ctxt.paths = []string{path.Join("testdata", "negative_tests1.go")}
+	ctxt.flags.noInference = true
+	ctxt.flags.force = "hex lit=B, empty map=A"
+	if err := ctxt.initCheckers(); err != nil {
+		t.Fatalf("init checkers: %v", err)
+	}
+	// Hex lit, empty map and the checks with a fixed suggestion.
+	if len(ctxt.checkers) != 3 {
+		t.Fatalf("expected only forced checkers to be enabled, got %d", len(ctxt.checkers))
+	}
+	for _, c := range ctxt.checkers {
+		if c.Operation().forced == nil {
+			t.Errorf("%s: enabled without a forced suggestion", c.Operation().name)
+		}
+	}
+	if err := ctxt.collectAllCandidates(); err != nil {
+		t.Fatalf("collect candidates: %v", err)
+	}
+	if err := ctxt.assignSuggestions(); err != nil {
+		t.Fatalf("assign suggestions: %v", err)
+	}
+
+	var warnings []string
+	visitWarings(ctxt, func(pos token.Position, v, suggested *opVariant) {
+		warnings = append(warnings, v.op.name+": "+suggested.warning)
+	})
+	want := []string{
+		"hex lit: use A-F (upper case) digits",
+		"hex lit: use A-F (upper case) digits",
+		"hex lit: use A-F (upper case) digits",
+	}
+	if strings.Join(warnings, "\n") != strings.Join(want, "\n") {
+		t.Errorf("warnings mismatch:\nhave: %q\nwant: %q", warnings, want)
+	}
+}
+
+func TestBadForce(t *testing.T) {
+	tests := []string{
+		"hex lit",
+		"hex lit=C",
+		"hex lit=b",
+		"unknown=A",
+	}
+
+	for _, force := range tests {
+		ctxt := newTestContext(t)
+		ctxt.flags.force = force
+		if err := ctxt.initCheckers(); err == nil {
+			t.Errorf("%q: expected an error", force)
+		}
+	}
+}
+
+func TestScopes(t *testing.T) {
+	paths := []string{
+		"./" + path.Join("testdata", "multimodule", "a"),
+		"./" + path.Join("testdata", "multimodule", "nested", "b"),
+	}
+	tests := []struct {
+		scope    string
+		warnings int
+	}{
+		{"global", 1},
+		{"module", 0},
+		{"package", 0},
+	}
+
+	for _, test := range tests {
+		ctxt := newTestContext(t)
+		ctxt.paths = paths
+		ctxt.flags.scope = test.scope
+		runAnalysis(t, ctxt)
+		warnings := 0
+		visitWarings(ctxt, func(pos token.Position, v, suggested *opVariant) {
+			warnings++
+		})
+		if warnings != test.warnings {
+			t.Errorf("%s: warnings mismatch: have %d, want %d",
+				test.scope, warnings, test.warnings)
+		}
+	}
+}
+
+func TestFileFilters(t *testing.T) {
+	tests := []struct {
+		includeFiles string
+		excludeFiles string
+		warnings     int
+	}{
+		{"", "", 1},
+		{"", "b.go", 0},
+		{"", "a.go", 0},
+		{"", "testdata/filter/b.go", 0},
+		{"", "./testdata/filter/../filter/b.go", 0},
+		{"", "*.go", 0},
+		{"", "c.go, testdata/*.go", 1},
+
+		{"*.go", "", 1},
+		{"a.go", "", 0},
+		{"b.go", "", 0},
+		{"testdata/filter/*.go", "", 1},
+		{"c.go", "", 0},
+
+		// Include is applied first, then exclude.
+		{"*.go", "b.go", 0},
+		{"a.go,b.go", "c.go", 1},
+		{"a.go", "a.go", 0},
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("get working dir: %v", err)
+	}
+	for _, test := range tests {
+		ctxt := newTestContext(t)
+		ctxt.paths = []string{"./" + path.Join("testdata", "filter")}
+		ctxt.flags.includeFiles = test.includeFiles
+		ctxt.flags.excludeFiles = test.excludeFiles
+		if err := ctxt.initFilePatterns(wd); err != nil {
+			t.Fatalf("init file patterns: %v", err)
+		}
+		runAnalysis(t, ctxt)
+		warnings := 0
+		visitWarings(ctxt, func(pos token.Position, v, suggested *opVariant) {
+			warnings++
+		})
+		if warnings != test.warnings {
+			t.Errorf("%q/%q: warnings mismatch: have %d, want %d",
+				test.includeFiles, test.excludeFiles, warnings, test.warnings)
+		}
+	}
+}
+
+func TestInitFilePatterns(t *testing.T) {
+	wd := filepath.Join(string(filepath.Separator), "src", "project")
+	ctxt := newTestContext(t)
+	ctxt.flags.includeFiles = "*.go, pkg/*.go, ../other/a.go"
+	ctxt.flags.excludeFiles = filepath.Join(wd, "pkg", "b.go")
+	if err := ctxt.initFilePatterns(wd); err != nil {
+		t.Fatalf("init file patterns: %v", err)
+	}
+	wantInclude := []string{
+		"*.go",
+		filepath.Join(wd, "pkg", "*.go"),
+		filepath.Join(string(filepath.Separator), "src", "other", "a.go"),
+	}
+	if !reflect.DeepEqual(ctxt.includeFiles, wantInclude) {
+		t.Errorf("include patterns mismatch:\nhave: %q\nwant: %q", ctxt.includeFiles, wantInclude)
+	}
+	if wantExclude := []string{ctxt.flags.excludeFiles}; !reflect.DeepEqual(ctxt.excludeFiles, wantExclude) {
+		t.Errorf("exclude patterns mismatch:\nhave: %q\nwant: %q", ctxt.excludeFiles, wantExclude)
+	}
+
+	ctxt.flags.excludeFiles = "[a-"
+	if err := ctxt.initFilePatterns(wd); err == nil {
+		t.Errorf("expected an error for the malformed pattern")
+	}
+}
+
+func TestFailOnUndecided(t *testing.T) {
+	ctxt := newTestContext(t)
+	ctxt.paths = []string{"./" + path.Join("testdata", "undecided")}
+	runAnalysis(t, ctxt)
+
+	if err := ctxt.checkUndecided(); err != nil {
+		t.Errorf("expected no error without -fail-on-undecided, got %v", err)
+	}
+	ctxt.flags.failUndecided = true
+	err := ctxt.checkUndecided()
+	if err == nil || err.Error() != "undecided operations: empty map" {
+		t.Errorf("expected empty map to be reported as undecided, got %v", err)
+	}
+}
+
+func TestInertOperations(t *testing.T) {
+	var buf bytes.Buffer
+	ctxt := newTestContext(t)
+	ctxt.logger = log.New(&buf, "", 0)
+	ctxt.paths = []string{"./" + path.Join("testdata", "filter")}
+	ctxt.flags.verbose = true
+	runAnalysis(t, ctxt)
+
+	output := buf.String()
+	if !strings.Contains(output, "hex lit: no variants found") {
+		t.Errorf("expected hex lit to be reported as inert, got:\n%s", output)
+	}
+	if strings.Contains(output, "empty map: no variants found") {
+		t.Errorf("empty map should not be reported as inert, got:\n%s", output)
+	}
+}
+
+func TestImportAlias(t *testing.T) {
+	ctxt := newTestContext(t)
+	ctxt.paths = []string{"./" + path.Join("testdata", "importalias")}
+	runAnalysis(t, ctxt)
+
+	var warnings []string
+	visitWarings(ctxt, func(pos token.Position, v, suggested *opVariant) {
+		warnings = append(warnings, fmt.Sprintf("%s:%d: %s: %s",
+			path.Base(pos.Filename), pos.Line, v.op.name, suggested.warning))
+	})
+	want := []string{
+		"c.go:4: import alias: import this package with alias, like other files do",
+		"c.go:5: import alias: import this package without alias, like other files do",
+	}
+	if strings.Join(warnings, "\n") != strings.Join(want, "\n") {
+		t.Errorf("warnings mismatch:\nhave: %q\nwant: %q", warnings, want)
+	}
+}
+
+func TestImportAliasNames(t *testing.T) {
+	ctxt := newTestContext(t)
+	ctxt.paths = []string{"./" + path.Join("testdata", "aliasnames")}
+	runAnalysis(t, ctxt)
+
+	var warnings []string
+	visitWarings(ctxt, func(pos token.Position, v, suggested *opVariant) {
+		warnings = append(warnings, fmt.Sprintf("%s:%d: %s: %s",
+			path.Base(pos.Filename), pos.Line, v.op.name, suggested.warning))
+	})
+	want := []string{
+		"c.go:3: import alias: import this package with alias, like other files do",
+	}
+	if strings.Join(warnings, "\n") != strings.Join(want, "\n") {
+		t.Errorf("warnings mismatch:\nhave: %q\nwant: %q", warnings, want)
+	}
+}
+
+func TestLoggerFields(t *testing.T) {
+	ctxt := newTestContext(t)
+	ctxt.paths = []string{"./" + path.Join("testdata", "logger")}
+	ctxt.flags.pedantic = true
+	ctxt.flags.loggers = "github.com/Quasilyte/go-consistent/testdata/logger/zap"
+	runAnalysis(t, ctxt)
+
+	// Field and option constructors are not counted,
+	// so 2 log calls win over the single zap call.
+	var warnings []string
+	visitWarings(ctxt, func(pos token.Position, v, suggested *opVariant) {
+		if v.op.name == "logger" {
+			warnings = append(warnings, fmt.Sprintf("%s:%d: %s", path.Base(pos.Filename), pos.Line, suggested.warning))
+		}
+	})
+	want := []string{"a.go:12: use the log package instead of the structured logger"}
+	if strings.Join(warnings, "\n") != strings.Join(want, "\n") {
+		t.Errorf("warnings mismatch:\nhave: %q\nwant: %q", warnings, want)
+	}
+}
+
+func TestIgnoredFiles(t *testing.T) {
+	dir := path.Join("testdata", "buildtags")
+	ctxt := newTestContext(t)
+	ctxt.flags.exclude = `^unsafe$|^builtin$`
+	ctxt.flags.targets = []string{
+		path.Join(dir, "ok.go"),
+		path.Join(dir, "_underscore.go"),
+		path.Join(dir, ".dot.go"),
+		path.Join(dir, "legacy_ignore.go"),
+		path.Join(dir, "modern_ignore.go"),
+		"./" + dir,
+	}
+	if err := ctxt.resolveTargets(); err != nil {
+		t.Fatalf("resolve targets: %v", err)
+	}
+	want := []string{
+		path.Join(dir, "ok.go"),
+		"./" + dir,
+	}
+	if strings.Join(ctxt.paths, " ") != strings.Join(want, " ") {
+		t.Errorf("paths mismatch:\nhave: %q\nwant: %q", ctxt.paths, want)
+	}
+
+	if err := ctxt.initCheckers(); err != nil {
+		t.Fatalf("init checkers: %v", err)
+	}
+	ctxt.paths = want[1:]
+	if err := ctxt.collectAllCandidates(); err != nil {
+		t.Fatalf("collect candidates: %v", err)
+	}
+	if ctxt.files != 1 {
+		t.Errorf("expected only ok.go to be checked, got %d files", ctxt.files)
+	}
+}
+
+func TestJSONLines(t *testing.T) {
+	var buf bytes.Buffer
+	ctxt := newTestContext(t)
+	ctxt.out = &buf
+	ctxt.paths = []string{"./" + path.Join("testdata", "importalias")}
+	ctxt.flags.format = "jsonl"
+	runAnalysis(t, ctxt)
+	if err := ctxt.printWarnings(); err != nil {
+		t.Fatalf("print warnings: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 2 || ctxt.warnings != 2 {
+		t.Fatalf("expected 2 warnings, got %d lines:\n%s", len(lines), buf.String())
+	}
+	var w jsonWarning
+	if err := json.Unmarshal([]byte(lines[1]), &w); err != nil {
+		t.Fatalf("decode %q: %v", lines[1], err)
+	}
+	want := jsonWarning{
+		Filename: "c.go",
+		Line:     5,
+		Column:   2,
+		Op:       "import alias",
+		Message:  "import this package without alias, like other files do",
+	}
+	w.Filename = path.Base(w.Filename)
+	if w != want {
+		t.Errorf("warning mismatch:\nhave: %+v\nwant: %+v", w, want)
+	}
+}
+
+func TestReference(t *testing.T) {
+	dir := path.Join("testdata", "filter")
+	tests := []struct {
+		reference string
+		warnings  int
+	}{
+		{"", 1},
+		{path.Join(dir, "a.go"), 1},
+		{path.Join(dir, "b.go"), 2},
+		{dir, 1},
+		{path.Join(dir, "b.go") + "," + path.Join("testdata", "multimodule"), 2},
+		{path.Join("testdata", "fil"), 0},
+	}
+
+	for _, test := range tests {
+		ctxt := newTestContext(t)
+		ctxt.paths = []string{"./" + dir}
+		ctxt.flags.reference = test.reference
+		runAnalysis(t, ctxt)
+		warnings := 0
+		visitWarings(ctxt, func(pos token.Position, v, suggested *opVariant) {
+			warnings++
+		})
+		if warnings != test.warnings {
+			t.Errorf("%q: warnings mismatch: have %d, want %d",
+				test.reference, warnings, test.warnings)
+		}
+	}
+}
+
+func TestExportedOnly(t *testing.T) {
+	ctxt := newTestContext(t)
+	ctxt.paths = []string{"./" + path.Join("testdata", "exported")}
+	ctxt.flags.exportedOnly = true
+	ctxt.flags.force = "empty map=A"
+	runAnalysis(t, ctxt)
+
+	var lines []int
+	visitWarings(ctxt, func(pos token.Position, v, suggested *opVariant) {
+		lines = append(lines, pos.Line)
+	})
+	want := []int{8, 10, 16, 18, 26, 31, 43, 43}
+	if !reflect.DeepEqual(lines, want) {
+		t.Errorf("warning lines mismatch:\nhave: %v\nwant: %v", lines, want)
+	}
+}
+
+func TestShowSource(t *testing.T) {
+	var buf bytes.Buffer
+	ctxt := newTestContext(t)
+	ctxt.out = &buf
+	ctxt.paths = []string{"./" + path.Join("testdata", "filter")}
+	ctxt.flags.showSource = true
+	runAnalysis(t, ctxt)
+	if err := ctxt.printWarnings(); err != nil {
+		t.Fatalf("print warnings: %v", err)
+	}
+
+	lines := strings.Split(buf.String(), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected warning and 2 source lines, got:\n%s", buf.String())
+	}
+	want := "\t\t_ = make(map[int]int)\n\t\t    ^"
+	if have := lines[1] + "\n" + lines[2]; have != want {
+		t.Errorf("source output mismatch:\nhave: %q\nwant: %q", have, want)
+	}
+}
+
+func TestGroupByOperation(t *testing.T) {
+	var buf bytes.Buffer
+	ctxt := newTestContext(t)
+	ctxt.out = &buf
+	ctxt.paths = []string{"./" + path.Join("testdata", "importalias")}
+	ctxt.flags.groupBy = "operation"
+	runAnalysis(t, ctxt)
+	if err := ctxt.printWarnings(); err != nil {
+		t.Fatalf("print warnings: %v", err)
+	}
+
+	dir, err := filepath.Abs(filepath.Join("testdata", "importalias"))
+	if err != nil {
+		t.Fatalf("abs: %v", err)
+	}
+	want := strings.Join([]string{
+		"import alias: import this package with alias, like other files do (warnings: 1)",
+		"\t" + filepath.Join(dir, "c.go") + ":4:2",
+		"import alias: import this package without alias, like other files do (warnings: 1)",
+		"\t" + filepath.Join(dir, "c.go") + ":5:2",
+		"",
+	}, "\n")
+	if buf.String() != want {
+		t.Errorf("output mismatch:\nhave: %q\nwant: %q", buf.String(), want)
+	}
+	if ctxt.warnings != 2 {
+		t.Errorf("expected 2 warnings, got %d", ctxt.warnings)
+	}
+}
+
+func TestCountOnly(t *testing.T) {
+	var buf bytes.Buffer
+	ctxt := newTestContext(t)
+	ctxt.out = &buf
+	ctxt.paths = []string{"./" + path.Join("testdata", "importalias")}
+	ctxt.flags.countOnly = true
+	ctxt.flags.showSource = true
+	runAnalysis(t, ctxt)
+	if err := ctxt.printWarnings(); err != nil {
+		t.Fatalf("print warnings: %v", err)
+	}
+	if buf.String() != "2\n" {
+		t.Errorf("output mismatch: have %q, want %q", buf.String(), "2\n")
+	}
+}
+
+func TestViolatedOperations(t *testing.T) {
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{"text", "import alias: 2\nempty map: 1\n"},
+		{"jsonl", `{"op":"import alias","warnings":2}` + "\n" + `{"op":"empty map","warnings":1}` + "\n"},
+	}
+	for _, test := range tests {
+		var buf bytes.Buffer
+		ctxt := newTestContext(t)
+		ctxt.out = &buf
+		ctxt.paths = []string{
+			"./" + path.Join("testdata", "importalias"),
+			"./" + path.Join("testdata", "filter"),
+		}
+		ctxt.flags.operations = true
+		ctxt.flags.format = test.format
+		runAnalysis(t, ctxt)
+		if err := ctxt.printWarnings(); err != nil {
+			t.Fatalf("print warnings: %v", err)
+		}
+		if buf.String() != test.want {
+			t.Errorf("%s: output mismatch:\nhave: %q\nwant: %q", test.format, buf.String(), test.want)
+		}
+		if ctxt.warnings != 3 {
+			t.Errorf("%s: warnings count: have %d, want 3", test.format, ctxt.warnings)
+		}
+	}
+}
+
+func TestSummary(t *testing.T) {
+	var buf bytes.Buffer
+	ctxt := newTestContext(t)
+	ctxt.logger = log.New(&buf, "", 0)
+	ctxt.paths = []string{
+		"./" + path.Join("testdata", "importalias"),
+		"./" + path.Join("testdata", "filter"),
+	}
+	ctxt.flags.summary = true
+	runAnalysis(t, ctxt)
+	steps := []func() error{
+		ctxt.printWarnings,
+		ctxt.printSummary,
+	}
+	for _, step := range steps {
+		if err := step(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected summary and 2 operation lines, got:\n%s", buf.String())
+	}
+	if !strings.HasPrefix(lines[0], "go-consistent: files=6 warnings=3 undecided=0 ") {
+		t.Errorf("unexpected summary line: %q", lines[0])
+	}
+	if lines[1] != "\timport alias: 2" || lines[2] != "\tempty map: 1" {
+		t.Errorf("unexpected breakdown:\n%s", strings.Join(lines[1:], "\n"))
+	}
+}
+
+func TestSuppressions(t *testing.T) {
+	var buf bytes.Buffer
+	ctxt := newTestContext(t)
+	ctxt.out = &buf
+	ctxt.paths = []string{"./" + path.Join("testdata", "suppress")}
+	ctxt.flags.reportUnused = true
+	runAnalysis(t, ctxt)
+	steps := []func() error{
+		ctxt.printWarnings,
+		ctxt.reportUnusedSuppressions,
+	}
+	for _, step := range steps {
+		if err := step(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	want := []string{
+		"a.go:15:6: empty map: use map[K]V{}",
+		"a.go:14:2: unused suppression",
+		"a.go:17:2: unused suppression",
+	}
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != len(want) {
+		t.Fatalf("output mismatch:\nhave:\n%s\nwant:\n%s", buf.String(), strings.Join(want, "\n"))
+	}
+	for i := range want {
+		if !strings.HasSuffix(lines[i], string(filepath.Separator)+want[i]) {
+			t.Errorf("line %d mismatch:\nhave: %s\nwant: %s", i, lines[i], want[i])
+		}
+	}
+	if ctxt.warnings != 3 {
+		t.Errorf("expected unused suppressions to be counted, got %d warnings", ctxt.warnings)
+	}
+}
+
+func TestPrintOperations(t *testing.T) {
+	var buf bytes.Buffer
+	ctxt := newTestContext(t)
+	ctxt.out = &buf
+	ctxt.flags.scope = "global"
+	ctxt.flags.force = "hex lit=B"
+	if err := ctxt.printOperations(); err != nil {
+		t.Fatalf("print operations: %v", err)
+	}
+
+	var ops []jsonOperation
+	if err := json.Unmarshal(buf.Bytes(), &ops); err != nil {
+		t.Fatalf("decode output: %v", err)
+	}
+	if len(ops) != len(ctxt.allCheckers()) {
+		t.Errorf("expected all operations to be listed, got %d", len(ops))
+	}
+	byName := make(map[string]jsonOperation)
+	for _, op := range ops {
+		byName[op.Name] = op
+		if op.Doc == "" {
+			t.Errorf("%s: empty doc", op.Name)
+		}
+	}
+	hexLit := byName["hex lit"]
+	if hexLit.Forced != "B" || hexLit.Pedantic || hexLit.Scope != "global" || len(hexLit.Variants) != 2 {
+		t.Errorf("unexpected hex lit entry: %+v", hexLit)
+	}
+	if errCompare := byName["err compare"]; errCompare.Preferred != "B" || !errCompare.Pedantic {
+		t.Errorf("unexpected err compare entry: %+v", errCompare)
+	}
+	if nilCheck := byName["nil check"]; nilCheck.Forced != "A" {
+		t.Errorf("unexpected nil check entry: %+v", nilCheck)
+	}
+}
+
+func TestListOperations(t *testing.T) {
+	for _, flagName := range []string{"", "list-json", "rules-doc"} {
+		var buf bytes.Buffer
+		ctxt := newTestContext(t)
+		ctxt.out = &buf
+		ctxt.flags.listJSON = flagName == "list-json"
+		ctxt.flags.rulesDoc = flagName == "rules-doc"
+		err := ctxt.listOperations()
+		switch {
+		case flagName == "" && (err != nil || buf.Len() != 0):
+			t.Errorf("no flags: unexpected result: %v, %d bytes printed", err, buf.Len())
+		case flagName != "" && (err != errDone || buf.Len() == 0):
+			t.Errorf("-%s: unexpected result: %v, %d bytes printed", flagName, err, buf.Len())
+		}
+	}
+}
+
+func TestPrintRulesDoc(t *testing.T) {
+	var buf bytes.Buffer
+	ctxt := newTestContext(t)
+	ctxt.out = &buf
+	ctxt.flags.scope = "global"
+	ctxt.flags.force = "hex lit=B"
+	if err := ctxt.printRulesDoc(); err != nil {
+		t.Fatalf("print rules doc: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != len(ctxt.allCheckers())+2 {
+		t.Errorf("expected all operations to be listed, got %d lines", len(lines))
+	}
+	want := []string{
+		"| hex lit | global | A: use a-f (lower case) digits<br>B: use A-F (upper case) digits | fixed B | lower case vs upper case hex digits |",
+		"| err compare | global | A: compare errors with `err == ErrFoo`<br>B: compare errors with `errors.Is(err, ErrFoo)` | pedantic, preferred B | == vs errors.Is for sentinel error comparisons |",
+		"| empty map | global | A: use make(map[K]V)<br>B: use map[K]V{} | inferred | make vs literal for empty maps |",
+	}
+	for _, line := range want {
+		if !strings.Contains(buf.String(), line+"\n") {
+			t.Errorf("missing line: %s", line)
+		}
+	}
+	for i, line := range lines {
+		if !strings.HasPrefix(line, "|") || strings.Count(strings.Replace(line, `\|`, "", -1), "|") != 6 {
+			t.Errorf("line %d: malformed table row: %s", i+1, line)
+		}
+	}
+}
+
+func TestLoopVarNotes(t *testing.T) {
+	tests := []struct {
+		dir     string
+		warning string
+	}{
+		{"old", "a.go:9: goroutine args: pass variables to goroutines as arguments, like in `go func(x T) {...}(x)` (note: goroutine captures the x loop variable by reference)"},
+		{"new", "a.go:9: goroutine args: pass variables to goroutines as arguments, like in `go func(x T) {...}(x)`"},
+	}
+
+	for _, test := range tests {
+		ctxt := newTestContext(t)
+		ctxt.paths = []string{"./" + path.Join("testdata", "loopvars", test.dir)}
+		ctxt.flags.pedantic = true
+		runAnalysis(t, ctxt)
+		var warnings []string
+		visitWarings(ctxt, func(pos token.Position, v, suggested *opVariant) {
+			warnings = append(warnings, fmt.Sprintf("%s:%d: %s%s",
+				path.Base(pos.Filename), pos.Line, warningText(v, suggested), ctxt.noteOf(pos)))
 		})
+		if strings.Join(warnings, "\n") != test.warning {
+			t.Errorf("%s: warnings mismatch:\nhave: %q\nwant: %q", test.dir, warnings, test.warning)
+		}
 	}
 }
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"go/ast"
+	"go/token"
+)
+
+// posRange is a source file range.
+//
+// Only the line and column are compared, since the
+// candidate locations don't preserve the offsets.
+type posRange struct {
+	start token.Position
+	end   token.Position
+}
+
+func (r posRange) contains(pos token.Position) bool {
+	return !posLess(pos, r.start) && posLess(pos, r.end)
+}
+
+// posLess reports whether x is located before y in the same file.
+func posLess(x, y token.Position) bool {
+	if x.Line != y.Line {
+		return x.Line < y.Line
+	}
+	return x.Column < y.Column
+}
+
+// collectExportedRanges records the source ranges of the f file
+// exported declarations if -exported-only flag is set.
+//
+// Exported declarations are the exported functions,
+// exported methods of exported types and the type, var and const
+// specs that declare at least one exported name.
+func (ctxt *context) collectExportedRanges(f *ast.File) {
+	if !ctxt.flags.exportedOnly {
+		return
+	}
+	add := func(n ast.Node) {
+		r := posRange{start: ctxt.fset.Position(n.Pos()), end: ctxt.fset.Position(n.End())}
+		if ctxt.exportedRanges == nil {
+			ctxt.exportedRanges = make(map[string][]posRange)
+		}
+		ctxt.exportedRanges[r.start.Filename] = append(ctxt.exportedRanges[r.start.Filename], r)
+	}
+
+	for _, decl := range f.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			if isExportedFunc(decl) {
+				add(decl)
+			}
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				switch spec := spec.(type) {
+				case *ast.TypeSpec:
+					if spec.Name.IsExported() {
+						add(spec)
+					}
+				case *ast.ValueSpec:
+					for _, name := range spec.Names {
+						if name.IsExported() {
+							add(spec)
+							break
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+// isExportedFunc reports whether decl is an exported function
+// or an exported method of an exported type.
+func isExportedFunc(decl *ast.FuncDecl) bool {
+	if !decl.Name.IsExported() {
+		return false
+	}
+	if decl.Recv == nil || len(decl.Recv.List) == 0 {
+		return true
+	}
+	typ := decl.Recv.List[0].Type
+	for {
+		switch x := typ.(type) {
+		case *ast.StarExpr:
+			typ = x.X
+		case *ast.ParenExpr:
+			typ = x.X
+		case *ast.IndexExpr:
+			typ = x.X
+		case *ast.Ident:
+			return x.IsExported()
+		default:
+			return false
+		}
+	}
+}
+
+// isExportedPos reports whether pos belongs to an exported declaration.
+// Always reports true if -exported-only flag is not set.
+func (ctxt *context) isExportedPos(pos token.Position) bool {
+	if !ctxt.flags.exportedOnly {
+		return true
+	}
+	for _, r := range ctxt.exportedRanges[pos.Filename] {
+		if r.contains(pos) {
+			return true
+		}
+	}
+	return false
+}
@@ -1,5 +1,7 @@
 module github.com/Quasilyte/go-consistent
 
+go 1.27.1
+
 require (
 	github.com/go-toolsmith/astcast v1.0.0
 	github.com/go-toolsmith/astequal v1.0.0
@@ -9,3 +11,5 @@ require (
 	github.com/kisielk/gotool v1.0.0
 	golang.org/x/tools v0.0.0-20190110163146-51295c7ec13a
 )
+
+require github.com/go-toolsmith/strparse v1.0.0 // indirect
@@ -22,6 +22,9 @@ func ParseTestFile(filename string) (*TestFile, error) {
 type TestFile struct {
 	// Matchers is a mapping from source code line to the
 	// list of matchers for it.
+	//
+	// Matchers come from the magic comments that precede the line,
+	// followed by the patterns of its trailing `// want` comment.
 	Matchers map[int][]*Matcher
 
 	text string
@@ -90,3 +93,7 @@ func (p *TestParser) ParseFile(filename string, r io.Reader) (*TestFile, error)
 }
 
 var defaultMatcherRE = regexp.MustCompile(`^\s*//([=~]) (.*)`)
+
+// wantRE matches trailing `// want "pattern"` comments,
+// see analysistest package for the convention description.
+var wantRE = regexp.MustCompile(`^//\s*want\s+(.*)$`)
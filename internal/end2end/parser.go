@@ -2,9 +2,12 @@ package end2end
 
 import (
 	"fmt"
+	goscanner "go/scanner"
 	"go/token"
 	"regexp"
+	"strconv"
 	"strings"
+	"text/scanner"
 )
 
 func (p *TestParser) parseFile(filename string, data []byte) (*TestFile, error) {
@@ -12,6 +15,7 @@ func (p *TestParser) parseFile(filename string, data []byte) (*TestFile, error)
 	var pending []*Matcher
 
 	f := &TestFile{Matchers: matchers, text: string(data)}
+	comments := lineComments(filename, data)
 
 	for i, l := range strings.Split(f.text, "\n") {
 		line := i + 1
@@ -41,6 +45,16 @@ func (p *TestParser) parseFile(filename string, data []byte) (*TestFile, error)
 			matchers[line] = append([]*Matcher{}, pending...)
 			pending = pending[:0] // Clear pending list
 		}
+
+		if !ok {
+			want, err := p.fetchWant(comments[line], token.Position{Filename: filename, Line: line})
+			if err != nil {
+				return nil, err
+			}
+			if len(want) != 0 {
+				matchers[line] = append(matchers[line], want...)
+			}
+		}
 	}
 
 	return f, nil
@@ -58,3 +72,62 @@ func (p *TestParser) fetchMatcher(l string) (op, text string, ok bool) {
 	}
 	return m[1], m[2], true
 }
+
+// lineComments maps the data lines to their first `//` comments.
+// Comments are found by the Go scanner, so `//` inside string
+// literals, like in "http://x", are not treated as comments.
+func lineComments(filename string, data []byte) map[int]string {
+	comments := make(map[int]string)
+	fset := token.NewFileSet()
+	file := fset.AddFile(filename, -1, len(data))
+	var s goscanner.Scanner
+	// Syntax errors are reported by the checked program itself.
+	s.Init(file, data, nil, goscanner.ScanComments)
+	for {
+		pos, tok, lit := s.Scan()
+		if tok == token.EOF {
+			return comments
+		}
+		if tok != token.COMMENT || !strings.HasPrefix(lit, "//") {
+			continue
+		}
+		line := file.Line(pos)
+		if _, ok := comments[line]; !ok {
+			comments[line] = lit
+		}
+	}
+}
+
+// fetchWant parses the analysistest-style `// want "re1" "re2"` comment,
+// each pattern becomes a regexp matcher for the comment line.
+// Patterns can be written as interpreted or raw Go string literals.
+func (p *TestParser) fetchWant(comment string, pos token.Position) ([]*Matcher, error) {
+	m := wantRE.FindStringSubmatch(comment)
+	if m == nil {
+		return nil, nil
+	}
+
+	var list []*Matcher
+	var s scanner.Scanner
+	s.Init(strings.NewReader(m[1]))
+	s.Mode = scanner.ScanStrings | scanner.ScanRawStrings
+	s.Error = func(*scanner.Scanner, string) {}
+	for tok := s.Scan(); tok != scanner.EOF; tok = s.Scan() {
+		if tok != scanner.String && tok != scanner.RawString {
+			return nil, fmt.Errorf("%s: want: expected string literal, found %q", pos, s.TokenText())
+		}
+		text, err := strconv.Unquote(s.TokenText())
+		if err != nil {
+			return nil, fmt.Errorf("%s: want: %v", pos, err)
+		}
+		re, err := regexp.Compile(text)
+		if err != nil {
+			return nil, fmt.Errorf("%s: want: %v", pos, err)
+		}
+		list = append(list, &Matcher{text: text, pos: pos, re: re})
+	}
+	if len(list) == 0 {
+		return nil, fmt.Errorf("%s: want: expected at least one pattern", pos)
+	}
+	return list, nil
+}
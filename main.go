@@ -1,14 +1,27 @@
 package main
 
 import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"go/ast"
+	"go/build"
 	"go/token"
 	"go/types"
+	"io"
+	"io/ioutil"
 	"log"
 	"os"
+	"path/filepath"
 	"regexp"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/go-toolsmith/astinfo"
 	"github.com/go-toolsmith/pkgload"
@@ -18,42 +31,159 @@ import (
 
 var generatedFileCommentRE = regexp.MustCompile("Code generated .* DO NOT EDIT.")
 
+// version is a tool version that can be set during the build:
+//
+//	go build -ldflags "-X main.version=v1.2.3"
+//
+// If it's empty, the module version from the build info is used.
+var version string
+
 func main() {
-	log.SetFlags(0)
-	var ctxt context
+	ctxt := context{
+		logger:  log.New(os.Stderr, "", 0),
+		out:     os.Stdout,
+		started: time.Now(),
+	}
 
-	steps := []struct {
-		name string
-		fn   func() error
-	}{
+	steps := []runStep{
 		{"parse flags", ctxt.parseFlags},
+		{"load config", ctxt.loadConfig},
+		{"list operations", ctxt.listOperations},
+		{"load changed lines", ctxt.loadChangedLines},
+		{"load overlay", ctxt.loadOverlay},
+		{"open output", ctxt.openOutput},
+	}
+	for _, step := range steps {
+		err := step.fn()
+		if err == errDone {
+			return
+		}
+		if err != nil {
+			ctxt.logger.Fatalf("%s: %v", step.name, err)
+		}
+	}
+
+	if ctxt.flags.watch {
+		err := ctxt.watch()
+		ctxt.closeOutput()
+		ctxt.logger.Fatalf("watch: %v", err)
+	}
+
+	for _, step := range ctxt.analysisSteps() {
+		if err := step.fn(); err != nil {
+			// Keep the warnings printed so far.
+			ctxt.closeOutput()
+			ctxt.logger.Fatalf("%s: %v", step.name, err)
+		}
+	}
+	if err := ctxt.closeOutput(); err != nil {
+		ctxt.logger.Fatalf("close output: %v", err)
+	}
+
+	if ctxt.warnings != 0 {
+		os.Exit(1)
+	}
+}
+
+// runStep is a named main step.
+type runStep struct {
+	name string
+	fn   func() error
+}
+
+// errDone is returned by the main steps that did all the work,
+// like the -version printing, so main stops without an error.
+var errDone = errors.New("done")
+
+// analysisSteps returns the steps that check the targets,
+// they're executed after the flags and the config are loaded.
+func (ctxt *context) analysisSteps() []runStep {
+	return []runStep{
 		{"resolve targets", ctxt.resolveTargets},
 		{"init checkers", ctxt.initCheckers},
 		{"collect candidates", ctxt.collectAllCandidates},
+		{"load inferred cache", ctxt.loadInferredCache},
 		{"assign suggestions", ctxt.assignSuggestions},
+		{"save inferred cache", ctxt.saveInferredCache},
 		{"print warnings", ctxt.printWarnings},
-	}
-
-	for _, step := range steps {
-		if err := step.fn(); err != nil {
-			log.Fatalf("%s: %v", step.name, err)
-		}
+		{"report unused suppressions", ctxt.reportUnusedSuppressions},
+		{"print summary", ctxt.printSummary},
+		{"check undecided", ctxt.checkUndecided},
 	}
 }
 
+// context holds the entire state of a single analysis run.
+//
+// Every context is self-contained: it owns its file set, checkers and
+// the usage counters of their operations, so independent contexts can
+// be used concurrently. A single context is not safe for concurrent use.
+//
+// context.parseFlags is the only exception, since it uses the global
+// flag.CommandLine set; it's only intended to be called from main.
 type context struct {
 	// flags is an (effectively) immutable struct that holds all command-line
 	// arguments as they were passed to the program.
 	//
 	// For per-argument documentation see context.parseFlags.
 	flags struct {
-		pedantic bool
-		verbose  bool
-		debug    bool
-		targets  []string
-		exclude  string
+		pedantic         bool
+		verbose          bool
+		debug            bool
+		noInference      bool
+		summary          bool
+		version          bool
+		chainDepth       int
+		targets          []string
+		exclude          string
+		excludeFiles     string
+		includeFiles     string
+		force            string
+		scope            string
+		format           string
+		reference        string
+		showSource       bool
+		groupBy          string
+		config           string
+		countOnly        bool
+		noConfig         bool
+		failUndecided    bool
+		reportUnused     bool
+		inferredCache    string
+		changedLines     string
+		diffRoot         string
+		negativePrefixes string
+		loggers          string
+		listJSON         bool
+		rulesDoc         bool
+		exportedOnly     bool
+		out              string
+		overlay          string
+		debugAST         bool
+		archive          string
+		preferRecent     bool
+		watch            bool
+		operations       bool
 	}
 
+	// inferredCache holds the suggestions loaded from the -inferred-cache file.
+	// Nil if no cache is used.
+	inferredCache *inferredCache
+
+	// config holds the settings loaded from the -config file.
+	// Nil if no config is used.
+	config *config
+
+	// logger is used for diagnostics printing (info, debug and build errors).
+	logger *log.Logger
+
+	// out is a destination for the warnings output.
+	out io.Writer
+
+	// outFile and outBuf are the -out file and its out writer.
+	// Nil if the warnings are written to the standard output.
+	outFile *os.File
+	outBuf  *bufio.Writer
+
 	paths []string
 
 	locs *locationMap
@@ -65,6 +195,86 @@ type context struct {
 	checkers []checker
 
 	candidates []candidate
+
+	// scopeID is an inference scope ID of the package being checked.
+	//
+	// Candidates are only compared to other candidates that have
+	// the same scope ID, see -scope flag.
+	scopeID int
+
+	// opScopeIDs maps scope overrides to the inference scope IDs
+	// of the package being checked, see operation.scope.
+	opScopeIDs map[string]int
+
+	// scopeIDs maps inference scope keys to their IDs.
+	scopeIDs map[string]int
+
+	// scopeKeys maps inference scope IDs to their keys.
+	scopeKeys []string
+
+	// moduleRoots maps directories to their go.mod root directories.
+	moduleRoots map[string]string
+
+	// suggestions maps operations to their suggested variants.
+	//
+	// Updated during the context.assignSuggestions.
+	suggestions map[scopedOp]*opVariant
+
+	// overlay maps absolute file names to their contents
+	// that replace the on-disk versions, see -overlay.
+	overlay map[string][]byte
+
+	// sources maps file names of the in-memory Go files, like the
+	// -archive files, to their contents, see collectSourceCandidates.
+	sources map[string][]byte
+
+	// includeFiles and excludeFiles are the -include-files and
+	// -exclude-files patterns, see context.initFilePatterns.
+	includeFiles []string
+	excludeFiles []string
+
+	// changedLines maps absolute file names to their line numbers
+	// that are reported, see -include-only-changed-lines.
+	// Nil if all lines are reported.
+	changedLines map[string]map[int]bool
+
+	// exportedRanges maps file names to the source ranges
+	// of their exported declarations, see -exported-only.
+	exportedRanges map[string][]posRange
+
+	// suppressions maps source lines to the ignore directives
+	// that cover them, see ignoreDirective.
+	suppressions map[suppressedLine][]*suppression
+
+	// suppressionList is a list of all ignore directives in the order
+	// they were collected.
+	suppressionList []*suppression
+
+	// notes maps candidate positions to the notes that are appended
+	// to their warnings, see context.addNote.
+	notes map[token.Position]string
+
+	// sourceLines caches file lines for the -show-source output.
+	sourceLines map[string][]string
+
+	// undecided is a number of scoped operations that have ambiguous suggestions.
+	undecided int
+
+	// undecidedOps is a list of operations names that have ambiguous
+	// suggestions in at least one scope, in the checkers order.
+	undecidedOps []string
+
+	// started is a time when the analysis started.
+	started time.Time
+
+	// files is a number of checked files.
+	files int
+
+	// warnings is a number of warnings reported by context.printWarnings.
+	warnings int
+
+	// opWarnings maps operation names to their warnings count.
+	opWarnings map[string]int
 }
 
 func (ctxt *context) parseFlags() error {
@@ -76,18 +286,196 @@ func (ctxt *context) parseFlags() error {
 		`turn on detailed program execution info printing`)
 	flag.StringVar(&ctxt.flags.exclude, "exclude", `^unsafe$|^builtin$`,
 		`import path excluding regexp`)
+	flag.StringVar(&ctxt.flags.excludeFiles, "exclude-files", "",
+		`comma-separated list of file path glob patterns to skip, like "*_string.go,internal/gen/*.go"`)
+	flag.StringVar(&ctxt.flags.includeFiles, "include-files", "",
+		`comma-separated list of file path glob patterns to check, other files are skipped`)
+	flag.StringVar(&ctxt.flags.config, "config", "",
+		`path to a YAML config file with per-operation settings (default is `+configFilename+` lookup)`)
+	flag.BoolVar(&ctxt.flags.noConfig, "no-config", false,
+		`don't load any config file, even if -config is given`)
+	flag.StringVar(&ctxt.flags.force, "force", "",
+		`comma-separated list of forced suggestions, like "empty map=B,hex lit=A"`)
+	flag.BoolVar(&ctxt.flags.noInference, "no-inference", false,
+		`only check operations that have a -force suggestion, skipping the inference`)
+	flag.BoolVar(&ctxt.flags.summary, "summary", false,
+		`print a single-line run summary to stderr after the warnings`)
+	flag.StringVar(&ctxt.flags.scope, "scope", "global",
+		`conventions inference scope: global, module or package`)
+	flag.StringVar(&ctxt.flags.reference, "reference", "",
+		`comma-separated list of files or dirs that define the conventions for all targets`)
+	flag.BoolVar(&ctxt.flags.countOnly, "count-only", false,
+		`print only the number of warnings instead of the warnings themselves`)
+	flag.BoolVar(&ctxt.flags.operations, "operations", false,
+		`print only the operations that have warnings and their warnings counts`)
+	flag.BoolVar(&ctxt.flags.showSource, "show-source", false,
+		`print the source line with a caret after every warning (text format only)`)
+	flag.StringVar(&ctxt.flags.groupBy, "group-by", "file",
+		`warnings grouping: file or operation (text format only)`)
+	flag.StringVar(&ctxt.flags.format, "format", "text",
+		`warnings output format: text or jsonl (one JSON object per line)`)
+	flag.StringVar(&ctxt.flags.out, "out", "",
+		`file to write the warnings to instead of the standard output`)
+	flag.IntVar(&ctxt.flags.chainDepth, "chain-depth", defaultChainDepth,
+		`min number of method calls that is considered to be a method chain`)
+	flag.BoolVar(&ctxt.flags.failUndecided, "fail-on-undecided", false,
+		`exit with non-zero status if any operation has an ambiguous suggestion`)
+	flag.BoolVar(&ctxt.flags.reportUnused, "report-unused-suppressions", false,
+		`report ignore directives that didn't suppress any warnings`)
+	flag.StringVar(&ctxt.flags.inferredCache, "inferred-cache", "",
+		`file to persist the inferred suggestions between the runs`)
+	flag.StringVar(&ctxt.flags.changedLines, "include-only-changed-lines", "",
+		`unified diff file (or - for stdin) to report only the warnings for its added lines`)
+	flag.StringVar(&ctxt.flags.diffRoot, "diff-root", "",
+		`directory the -include-only-changed-lines paths are relative to (default is the git repository root)`)
+	flag.StringVar(&ctxt.flags.negativePrefixes, "negative-prefixes", defaultNegativePrefixes,
+		`comma-separated first words of negatively named booleans for the double negation check`)
+	flag.StringVar(&ctxt.flags.loggers, "loggers", defaultLoggers,
+		`comma-separated import paths of the structured loggers for the logger check`)
+	flag.StringVar(&ctxt.flags.overlay, "overlay", "",
+		`JSON file (or - for stdin) that maps file names to the contents that replace them`)
+	flag.BoolVar(&ctxt.flags.exportedOnly, "exported-only", false,
+		`report only the warnings inside of the exported declarations`)
+	flag.BoolVar(&ctxt.flags.preferRecent, "prefer-recent", false,
+		`make recently modified files weigh more in the inference, for style migrations`)
+	flag.BoolVar(&ctxt.flags.watch, "watch", false,
+		`re-run the analysis every time the target files change`)
+	flag.StringVar(&ctxt.flags.archive, "archive", "",
+		`zip file with Go files to check without the extraction`)
+	flag.BoolVar(&ctxt.flags.listJSON, "list-json", false,
+		`print all operations and their variants as JSON and exit`)
+	flag.BoolVar(&ctxt.flags.rulesDoc, "rules-doc", false,
+		`print all operations as a markdown table and exit`)
+	flag.BoolVar(&ctxt.flags.version, "version", false,
+		`print the tool version and exit`)
+	flag.BoolVar(&ctxt.flags.debugAST, "debug-ast", false,
+		`print every matched node with its operation variant to stderr`)
 
+	flag.Usage = printUsage
 	flag.Parse()
 
+	if ctxt.flags.version {
+		fmt.Fprintf(ctxt.out, "go-consistent %s %s\n", toolVersion(), runtime.Version())
+		return errDone
+	}
+
+	switch ctxt.flags.scope {
+	case "global", "module", "package":
+		// OK.
+	default:
+		return fmt.Errorf("-scope: unexpected value %q", ctxt.flags.scope)
+	}
+	switch ctxt.flags.format {
+	case "text", "jsonl":
+		// OK.
+	default:
+		return fmt.Errorf("-format: unexpected value %q", ctxt.flags.format)
+	}
+	switch ctxt.flags.groupBy {
+	case "file", "operation":
+		// OK.
+	default:
+		return fmt.Errorf("-group-by: unexpected value %q", ctxt.flags.groupBy)
+	}
+	if ctxt.flags.chainDepth < 2 {
+		return fmt.Errorf("-chain-depth: expected a value >= 2, got %d", ctxt.flags.chainDepth)
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	if err := ctxt.initFilePatterns(wd); err != nil {
+		return err
+	}
+
 	ctxt.flags.targets = flag.Args()
-	if len(ctxt.flags.targets) == 0 {
+	if len(ctxt.flags.targets) == 0 && !ctxt.flags.listJSON && !ctxt.flags.rulesDoc && ctxt.flags.archive == "" {
 		return fmt.Errorf("not enough positional args (empty targets list)")
 	}
 
 	return nil
 }
 
+// hiddenFlags are developer-only flags that are not listed in the usage.
+var hiddenFlags = map[string]bool{
+	"debug-ast": true,
+}
+
+// printUsage is like the default flag.Usage, but skips the hiddenFlags.
+func printUsage() {
+	visible := flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+	visible.SetOutput(flag.CommandLine.Output())
+	flag.VisitAll(func(f *flag.Flag) {
+		if !hiddenFlags[f.Name] {
+			visible.Var(f.Value, f.Name, f.Usage)
+		}
+	})
+	fmt.Fprintf(visible.Output(), "Usage of %s:\n", os.Args[0])
+	visible.PrintDefaults()
+}
+
+// isBuildFile reports whether filename would be included into the package
+// by the go build: its name doesn't start with "_" or "." and its
+// build constraints, both //go:build and legacy // +build, are satisfied.
+//
+// Directory targets don't need this, since the loader already skips such files.
+// The -overlay contents are used for the files that are present there.
+func (ctxt *context) isBuildFile(filename string) bool {
+	buildCtxt := build.Default
+	buildCtxt.OpenFile = func(path string) (io.ReadCloser, error) {
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			return nil, err
+		}
+		data, err := ctxt.readSourceFile(abs)
+		if err != nil {
+			return nil, err
+		}
+		return ioutil.NopCloser(bytes.NewReader(data)), nil
+	}
+	ok, err := buildCtxt.MatchFile(filepath.Dir(filename), filepath.Base(filename))
+	return err != nil || ok
+}
+
+// toolVersion returns the tool version, see version.
+func toolVersion() string {
+	if version != "" {
+		return version
+	}
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" {
+		return info.Main.Version
+	}
+	return "(devel)"
+}
+
+func (ctxt *context) loadConfig() error {
+	if ctxt.flags.noConfig {
+		return nil
+	}
+	filename := ctxt.flags.config
+	if filename == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+		filename = findConfig(wd)
+		if filename == "" {
+			return nil
+		}
+		ctxt.infoPrintf("using %s config", filename)
+	}
+	conf, err := loadConfig(filename)
+	if err != nil {
+		return err
+	}
+	ctxt.config = conf
+	return nil
+}
+
 func (ctxt *context) resolveTargets() error {
+	if len(ctxt.flags.targets) == 0 {
+		return nil // Only the -archive is checked
+	}
 	ctxt.paths = gotool.ImportPaths(ctxt.flags.targets)
 	if len(ctxt.paths) == 0 {
 		return fmt.Errorf("targets resolved to an empty import paths list")
@@ -106,6 +494,17 @@ func (ctxt *context) resolveTargets() error {
 	}
 	ctxt.paths = paths
 
+	// Filter-out files that are not a part of the normal build.
+	paths = ctxt.paths[:0]
+	for _, path := range ctxt.paths {
+		if strings.HasSuffix(path, ".go") && !ctxt.isBuildFile(path) {
+			ctxt.infoPrintf("skip %q: ignored by the go build", path)
+			continue
+		}
+		paths = append(paths, path)
+	}
+	ctxt.paths = paths
+
 	if len(paths) == 0 {
 		ctxt.infoPrintf("import paths list is empty after filtering")
 	}
@@ -113,8 +512,10 @@ func (ctxt *context) resolveTargets() error {
 	return nil
 }
 
-func (ctxt *context) initCheckers() error {
-	checkers := []checker{
+// allCheckers returns all checkers in their reporting order,
+// both enabled and disabled ones.
+func (ctxt *context) allCheckers() []checker {
+	return []checker{
 		newUnitImportChecker(ctxt),
 		newZeroValPtrAllocChecker(ctxt),
 		newEmptySliceChecker(ctxt),
@@ -128,7 +529,84 @@ func (ctxt *context) initCheckers() error {
 		newArgListParensChecker(ctxt),
 		newNonZeroLenTestChecker(ctxt),
 		newDefaultCaseOrderChecker(ctxt),
+		newSliceConcatChecker(ctxt),
+		newTypeAssertChecker(ctxt),
+		newMapIndexChecker(ctxt),
+		newTypedNilReturnChecker(ctxt),
+		newScalarPtrAllocChecker(ctxt),
+		newIfInitChecker(ctxt),
+		newSliceCloneChecker(ctxt),
+		newDurationLitChecker(ctxt),
+		newSizedSliceMakeChecker(ctxt),
+		newRangeValueChecker(ctxt),
+		newChanDrainChecker(ctxt),
+		newPrintlnChecker(ctxt),
+		newVoidReturnChecker(ctxt),
+		newImportAliasChecker(ctxt),
+		newBlankImportChecker(ctxt),
+		newMethodChainChecker(ctxt),
+		newFieldsSplitChecker(ctxt),
+		newSliceDeleteChecker(ctxt),
+		newMapInitChecker(ctxt),
+		newSliceInitChecker(ctxt),
+		newDeferCloseChecker(ctxt),
+		newLineReaderChecker(ctxt),
+		newByteConvChecker(ctxt),
+		newRuneConvChecker(ctxt),
+		newErrScopeChecker(ctxt),
+		newErrCompareChecker(ctxt),
+		newNilCheckChecker(ctxt),
+		newStringBuilderChecker(ctxt),
+		newReceiverKindChecker(ctxt),
+		newFmtStringerChecker(ctxt),
+		newMultiAssignChecker(ctxt),
+		newEnvLookupChecker(ctxt),
+		newDoubleNegationChecker(ctxt),
+		newErrReturnChecker(ctxt),
+		newChanDirChecker(ctxt),
+		newRedundantParensChecker(ctxt),
+		newIotaRepeatChecker(ctxt),
+		newTestFailChecker(ctxt),
+		newStructTagChecker(ctxt),
+		newDotImportChecker(ctxt),
+		newStdImportGroupChecker(ctxt),
+		newStructVerbChecker(ctxt),
+		newZeroFieldChecker(ctxt),
+		newErrorStringChecker(ctxt),
+		newTestHelperChecker(ctxt),
+		newConstBaseChecker(ctxt),
+		newCtxParamChecker(ctxt),
+		newSliceBoundsChecker(ctxt),
+		newGoroutineArgsChecker(ctxt),
+		newDeferFuncChecker(ctxt),
+		newLoggerChecker(ctxt),
+		newSlogMsgChecker(ctxt),
+		newCtorResultChecker(ctxt),
 	}
+}
+
+func (ctxt *context) initCheckers() error {
+	checkers := ctxt.allCheckers()
+
+	if err := ctxt.applyForcedVariants(checkers); err != nil {
+		return fmt.Errorf("-force: %v", err)
+	}
+	if err := ctxt.applyConfig(checkers); err != nil {
+		return fmt.Errorf("config: %v", err)
+	}
+
+	enabled := checkers[:0]
+	for _, c := range checkers {
+		op := c.Operation()
+		if op.pedantic && !ctxt.flags.pedantic {
+			continue
+		}
+		if op.forced == nil && ctxt.flags.noInference {
+			continue
+		}
+		enabled = append(enabled, c)
+	}
+	checkers = enabled
 
 	variantID := 0
 	for _, c := range checkers {
@@ -136,6 +614,9 @@ func (ctxt *context) initCheckers() error {
 		if op.name == "" {
 			panic(fmt.Sprintf("%T: empty operation name", c))
 		}
+		if op.doc == "" {
+			panic(fmt.Sprintf("%T: empty operation doc", c))
+		}
 		for i, v := range op.variants {
 			if v.warning == "" {
 				panic(fmt.Sprintf("%T: empty warning for variant#%d", c, i))
@@ -152,6 +633,42 @@ func (ctxt *context) initCheckers() error {
 	return nil
 }
 
+// applyForcedVariants assigns operation forced variants using the -force flag.
+//
+// Every list element has a form of "name=X", where name is the operation
+// name and X is the variant letter, A for the first variant, B for the
+// second and so on.
+func (ctxt *context) applyForcedVariants(checkers []checker) error {
+	if ctxt.flags.force == "" {
+		return nil
+	}
+
+	ops := make(map[string]*operation, len(checkers))
+	for _, c := range checkers {
+		op := c.Operation()
+		ops[op.name] = op
+	}
+
+	for _, pair := range strings.Split(ctxt.flags.force, ",") {
+		eq := strings.IndexByte(pair, '=')
+		if eq == -1 {
+			return fmt.Errorf("%q: expected name=X", pair)
+		}
+		name := strings.TrimSpace(pair[:eq])
+		letter := strings.TrimSpace(pair[eq+1:])
+		op := ops[name]
+		if op == nil {
+			return fmt.Errorf("%q: unknown operation", name)
+		}
+		if len(letter) != 1 || letter[0] < 'A' || int(letter[0]-'A') >= len(op.variants) {
+			return fmt.Errorf("%q: bad variant %q", name, letter)
+		}
+		op.forced = op.variants[letter[0]-'A']
+	}
+
+	return nil
+}
+
 func (ctxt *context) collectAllCandidates() error {
 	for _, path := range ctxt.paths {
 		ctxt.infoPrintf("check %q", path)
@@ -159,34 +676,215 @@ func (ctxt *context) collectAllCandidates() error {
 			return fmt.Errorf("%s: %v", path, err)
 		}
 	}
+	if err := ctxt.collectArchiveCandidates(); err != nil {
+		return fmt.Errorf("%s: %v", ctxt.flags.archive, err)
+	}
 	return nil
 }
 
 func (ctxt *context) collectPackageCandidates(pkg *packages.Package) {
 	ctxt.info = pkg.TypesInfo
+	ctxt.scopeID = ctxt.scopeIDOf(ctxt.scopeKey(pkg, ctxt.flags.scope))
+	ctxt.opScopeIDs = nil
+	for _, c := range ctxt.checkers {
+		if scope := c.Operation().scope; scope != "" {
+			if ctxt.opScopeIDs == nil {
+				ctxt.opScopeIDs = make(map[string]int)
+			}
+			ctxt.opScopeIDs[scope] = ctxt.scopeIDOf(ctxt.scopeKey(pkg, scope))
+		}
+	}
 	for _, f := range pkg.Syntax {
 		isGenerated := len(f.Comments) != 0 &&
 			generatedFileCommentRE.MatchString(f.Comments[0].Text())
 		if isGenerated {
 			continue
 		}
+		filename := ctxt.fset.Position(f.Pos()).Filename
+		if !ctxt.isFileIncluded(filename) {
+			ctxt.debugPrintf("skip excluded %s", filename)
+			continue
+		}
+		ctxt.files++
 		ctxt.collectFileCandidates(f)
 	}
 }
 
+// isFileIncluded reports whether filename should be checked according to
+// the -include-files and -exclude-files flags.
+//
+// Include patterns are applied first, then exclude patterns
+// are applied to the included files.
+func (ctxt *context) isFileIncluded(filename string) bool {
+	if len(ctxt.includeFiles) != 0 && !matchesFile(ctxt.includeFiles, filename) {
+		return false
+	}
+	return !matchesFile(ctxt.excludeFiles, filename)
+}
+
+// initFilePatterns assigns the -include-files and -exclude-files patterns.
+//
+// Patterns with a path separator are relative to the wd working directory,
+// they're made absolute once, so the files don't need to be made relative.
+func (ctxt *context) initFilePatterns(wd string) error {
+	patternFlags := []struct {
+		name string
+		list string
+		dst  *[]string
+	}{
+		{"-exclude-files", ctxt.flags.excludeFiles, &ctxt.excludeFiles},
+		{"-include-files", ctxt.flags.includeFiles, &ctxt.includeFiles},
+	}
+	for _, f := range patternFlags {
+		*f.dst = nil
+		for _, pattern := range splitPatterns(f.list) {
+			if _, err := filepath.Match(pattern, ""); err != nil {
+				return fmt.Errorf("%s: %q: %v", f.name, pattern, err)
+			}
+			pattern = filepath.FromSlash(pattern)
+			if strings.ContainsRune(pattern, filepath.Separator) && !filepath.IsAbs(pattern) {
+				pattern = filepath.Join(wd, pattern)
+			}
+			*f.dst = append(*f.dst, pattern)
+		}
+	}
+	return nil
+}
+
+// splitPatterns splits comma-separated list of glob patterns.
+func splitPatterns(list string) []string {
+	var patterns []string
+	for _, pattern := range strings.Split(list, ",") {
+		if pattern = strings.TrimSpace(pattern); pattern != "" {
+			patterns = append(patterns, pattern)
+		}
+	}
+	return patterns
+}
+
+// matchesFile reports whether filename matches any of the glob patterns.
+//
+// Patterns with a path separator are matched against the absolute
+// filename, other patterns are matched against its base name.
+// See context.initFilePatterns.
+func matchesFile(patterns []string, filename string) bool {
+	for _, pattern := range patterns {
+		name := filename
+		if !strings.ContainsRune(pattern, filepath.Separator) {
+			name = filepath.Base(filename)
+		}
+		if ok, _ := filepath.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// scopeKey returns an inference scope key for pkg, according to the scope
+// (which is the -scope flag value or the operation scope override).
+func (ctxt *context) scopeKey(pkg *packages.Package, scope string) string {
+	switch scope {
+	case "package":
+		return pkg.PkgPath
+	case "module":
+		if len(pkg.GoFiles) == 0 {
+			return ""
+		}
+		return ctxt.moduleRoot(filepath.Dir(pkg.GoFiles[0]))
+	default:
+		return ""
+	}
+}
+
+func (ctxt *context) scopeIDOf(key string) int {
+	if ctxt.scopeIDs == nil {
+		// The zero scope ID is reserved for the global scope key.
+		ctxt.scopeIDs = map[string]int{"": 0}
+		ctxt.scopeKeys = []string{""}
+	}
+	id, ok := ctxt.scopeIDs[key]
+	if !ok {
+		id = len(ctxt.scopeIDs)
+		ctxt.scopeIDs[key] = id
+		ctxt.scopeKeys = append(ctxt.scopeKeys, key)
+	}
+	return id
+}
+
+// scopeKeyOf returns the inference scope key of the scope ID.
+// Unassigned IDs, like the zero scope ID of an empty context,
+// have the global scope key.
+func (ctxt *context) scopeKeyOf(id int) string {
+	if id < len(ctxt.scopeKeys) {
+		return ctxt.scopeKeys[id]
+	}
+	return ""
+}
+
+// moduleRoot returns the closest dir parent directory that contains go.mod file.
+// Returns empty string if there is no such directory.
+func (ctxt *context) moduleRoot(dir string) string {
+	if ctxt.moduleRoots == nil {
+		ctxt.moduleRoots = make(map[string]string)
+	}
+	if root, ok := ctxt.moduleRoots[dir]; ok {
+		return root
+	}
+	root := ""
+	if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+		root = dir
+	} else if parent := filepath.Dir(dir); parent != dir {
+		root = ctxt.moduleRoot(parent)
+	}
+	ctxt.moduleRoots[dir] = root
+	return root
+}
+
+// splitTarget returns a directory to run the loader from along with
+// the pattern that should be loaded to get the path package (or file).
+//
+// Local targets are loaded from their module root, so nested modules
+// resolve their imports using their own go.mod.
+func (ctxt *context) splitTarget(path string) (dir, pattern string) {
+	isFile := strings.HasSuffix(path, ".go")
+	if !isFile && !build.IsLocalImport(path) && !filepath.IsAbs(path) {
+		return "", path
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", path
+	}
+	pkgDir := abs
+	if isFile {
+		pkgDir = filepath.Dir(abs)
+	}
+	root := ctxt.moduleRoot(pkgDir)
+	if root == "" {
+		return "", path
+	}
+	rel, err := filepath.Rel(root, abs)
+	if err != nil {
+		return "", path
+	}
+	return root, "./" + filepath.ToSlash(rel)
+}
+
 func (ctxt *context) collectPathCandidates(path string) error {
 	ctxt.fset = token.NewFileSet()
 
+	dir, pattern := ctxt.splitTarget(path)
 	conf := &packages.Config{
-		Mode:  packages.LoadSyntax,
-		Fset:  ctxt.fset,
-		Tests: true,
+		Mode:    packages.LoadSyntax,
+		Fset:    ctxt.fset,
+		Tests:   true,
+		Dir:     dir,
+		Overlay: ctxt.overlay,
 	}
 
 	// TODO(Quasilyte): current approach is memory-efficient
 	// and does scale well with huge amounts of targets to check,
 	// but it's not very fast. Might want to optimize it a little bit.
-	pkgs, err := packages.Load(conf, path)
+	pkgs, err := packages.Load(conf, pattern)
 	if err != nil {
 		return err
 	}
@@ -194,7 +892,7 @@ func (ctxt *context) collectPathCandidates(path string) error {
 		ctxt.infoPrintf("got 0 packages for %q path", path)
 		return nil
 	}
-	if n := packages.PrintErrors(pkgs); n > 0 {
+	if n := ctxt.printBuildErrors(pkgs); n > 0 {
 		return fmt.Errorf("%d build errors", n)
 	}
 
@@ -213,44 +911,545 @@ func (ctxt *context) collectPathCandidates(path string) error {
 	return nil
 }
 
+// printBuildErrors is like packages.PrintErrors, but it uses ctxt.logger
+// instead of the os.Stderr.
+func (ctxt *context) printBuildErrors(pkgs []*packages.Package) int {
+	n := 0
+	packages.Visit(pkgs, nil, func(pkg *packages.Package) {
+		for _, err := range pkg.Errors {
+			ctxt.logger.Print(err)
+			n++
+		}
+	})
+	return n
+}
+
 func (ctxt *context) collectFileCandidates(f *ast.File) {
 	ctxt.astinfo = astinfo.Info{
 		Parents: make(map[ast.Node]ast.Node),
 	}
 	ctxt.astinfo.Origin = f
 	ctxt.astinfo.Resolve()
+	ctxt.collectSuppressions(f)
+	ctxt.collectExportedRanges(f)
 
-	for _, c := range ctxt.checkers {
-		for _, decl := range f.Decls {
-			ast.Inspect(decl, c.Visit)
+	// Every file is traversed only once, each node is dispatched to all checkers.
+	// When checker Visit returns false, the node children are not passed
+	// to that checker, but other checkers still receive them.
+	//
+	// skipDepth[i] is a depth of the node that was rejected by the i-th checker.
+	// Value of 0 means that checker is active.
+	skipDepth := make([]int, len(ctxt.checkers))
+	depth := 0
+	leave := func() {
+		for i := range skipDepth {
+			if skipDepth[i] >= depth {
+				skipDepth[i] = 0
+			}
 		}
+		depth--
+	}
+	visit := func(n ast.Node) bool {
+		if n == nil {
+			leave()
+			return true
+		}
+		depth++
+		active := false
+		for i, c := range ctxt.checkers {
+			if skipDepth[i] != 0 {
+				continue
+			}
+			if c.Visit(n) {
+				active = true
+			} else {
+				skipDepth[i] = depth
+			}
+		}
+		if !active {
+			// Inspect doesn't call visit(nil) for rejected nodes.
+			leave()
+		}
+		return active
+	}
+
+	for _, decl := range f.Decls {
+		ast.Inspect(decl, visit)
 	}
 }
 
 func (ctxt *context) assignSuggestions() error {
+	type scopedVariant struct {
+		scopeID   int
+		variantID int
+	}
+	isReference, err := ctxt.referenceMatcher()
+	if err != nil {
+		return err
+	}
+	weights := ctxt.fileWeights()
+	counts := make(map[scopedVariant]int)
+	for _, c := range ctxt.candidates {
+		filename := ctxt.locs.Get(c.locationID).Filename
+		if isReference != nil && !isReference(filename) {
+			continue
+		}
+		weight := 1
+		if weights != nil {
+			weight = weights[filename]
+		}
+		counts[scopedVariant{scopeID: c.scopeID, variantID: c.variantID}] += weight
+	}
+
+	numScopes := len(ctxt.scopeIDs)
+	if numScopes == 0 {
+		numScopes = 1
+	}
+	ctxt.suggestions = make(map[scopedOp]*opVariant)
+	ctxt.undecided = 0
+	ctxt.undecidedOps = nil
+	isUndecided := make(map[*operation]bool)
+	for scopeID := 0; scopeID < numScopes; scopeID++ {
+		count := func(v *opVariant) int {
+			return counts[scopedVariant{scopeID: scopeID, variantID: v.id}]
+		}
+		scopeKey := ctxt.scopeKeyOf(scopeID)
+		for _, c := range ctxt.checkers {
+			op := c.Operation()
+			suggested, undecided := op.suggest(count)
+			if cached := ctxt.cachedSuggestion(op, scopeKey); cached != nil && op.forced == nil {
+				suggested, undecided = cached, false
+			} else if op.forced == nil && count(suggested) == 0 {
+				// Nothing to infer from, possibly because of -reference.
+				continue
+			}
+			if op.forced == nil {
+				ctxt.cacheSuggestion(op, scopeKey, suggested)
+			}
+			ctxt.suggestions[scopedOp{scopeID: scopeID, op: op}] = suggested
+			if undecided {
+				ctxt.undecided++
+				isUndecided[op] = true
+			}
+		}
+	}
+
+	// Report ambiguous operations and operations that
+	// had no chance to produce any warnings.
 	for _, c := range ctxt.checkers {
 		op := c.Operation()
-		op.suggested = op.variants[0]
-		for _, v := range op.variants[1:] {
-			if v.count > op.suggested.count {
-				op.suggested = v
-			}
+		if isUndecided[op] {
+			ctxt.undecidedOps = append(ctxt.undecidedOps, op.name)
+			ctxt.infoPrintf("%s: can't decide between variants (%s)", op.name, op.doc)
+		}
+		total := 0
+		for _, v := range op.variants {
+			total += v.count
+		}
+		if total == 0 {
+			ctxt.infoPrintf("%s: no variants found, operation is inert (%s)", op.name, op.doc)
 		}
 	}
+
 	return nil
 }
 
+// jsonWarning is a warning representation for the -format=jsonl output.
+type jsonWarning struct {
+	Filename string `json:"filename"`
+	Line     int    `json:"line"`
+	Column   int    `json:"column"`
+	Op       string `json:"op"`
+	Message  string `json:"message"`
+}
+
+// jsonOpWarnings is an operation warnings count for the -format=jsonl -operations output.
+type jsonOpWarnings struct {
+	Op       string `json:"op"`
+	Warnings int    `json:"warnings"`
+}
+
+// jsonOperation is an operation representation for the -list-json output.
+type jsonOperation struct {
+	Name     string `json:"name"`
+	Doc      string `json:"doc"`
+	Scope    string `json:"scope"`
+	Pedantic bool   `json:"pedantic"`
+
+	// Forced and Preferred are the variant letters of
+	// operation.forced and operation.preferred, if any.
+	Forced    string `json:"forced,omitempty"`
+	Preferred string `json:"preferred,omitempty"`
+
+	Variants []jsonVariant `json:"variants"`
+}
+
+type jsonVariant struct {
+	Letter  string `json:"letter"`
+	Warning string `json:"warning"`
+}
+
+// listOperations prints all operations and returns errDone
+// if -list-json or -rules-doc flag is set.
+func (ctxt *context) listOperations() error {
+	var err error
+	switch {
+	case ctxt.flags.listJSON:
+		err = ctxt.printOperations()
+	case ctxt.flags.rulesDoc:
+		err = ctxt.printRulesDoc()
+	default:
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return errDone
+}
+
+// configuredCheckers returns all checkers with the -force
+// and config settings applied.
+func (ctxt *context) configuredCheckers() ([]checker, error) {
+	checkers := ctxt.allCheckers()
+	if err := ctxt.applyForcedVariants(checkers); err != nil {
+		return nil, fmt.Errorf("-force: %v", err)
+	}
+	if err := ctxt.applyConfig(checkers); err != nil {
+		return nil, fmt.Errorf("config: %v", err)
+	}
+	return checkers, nil
+}
+
+// printOperations prints all operations as a JSON array.
+// The -force and config settings are applied, so the output
+// reflects the effective operation settings.
+func (ctxt *context) printOperations() error {
+	checkers, err := ctxt.configuredCheckers()
+	if err != nil {
+		return err
+	}
+
+	ops := make([]jsonOperation, 0, len(checkers))
+	for _, c := range checkers {
+		op := c.Operation()
+		info := jsonOperation{
+			Name:     op.name,
+			Doc:      op.doc,
+			Scope:    op.scope,
+			Pedantic: op.pedantic,
+		}
+		if info.Scope == "" {
+			info.Scope = ctxt.flags.scope
+		}
+		for i, v := range op.variants {
+			letter := variantLetter(i)
+			info.Variants = append(info.Variants, jsonVariant{Letter: letter, Warning: v.warning})
+			switch v {
+			case op.forced:
+				info.Forced = letter
+			case op.preferred:
+				info.Preferred = letter
+			}
+		}
+		ops = append(ops, info)
+	}
+	data, err := json.MarshalIndent(ops, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(ctxt.out, "%s\n", data)
+	return err
+}
+
+// printRulesDoc prints all operations as a markdown table, see -rules-doc.
+// Like printOperations, it reflects the effective operation settings.
+func (ctxt *context) printRulesDoc() error {
+	checkers, err := ctxt.configuredCheckers()
+	if err != nil {
+		return err
+	}
+
+	// Pipes would split the table cells.
+	escape := strings.NewReplacer("|", `\|`).Replace
+	var buf bytes.Buffer
+	buf.WriteString("| Operation | Scope | Variants | Status | Description |\n")
+	buf.WriteString("|---|---|---|---|---|\n")
+	for _, c := range checkers {
+		op := c.Operation()
+		scope := op.scope
+		if scope == "" {
+			scope = ctxt.flags.scope
+		}
+		var variants, status []string
+		if op.pedantic {
+			status = append(status, "pedantic")
+		}
+		for i, v := range op.variants {
+			letter := variantLetter(i)
+			variants = append(variants, letter+": "+escape(v.warning))
+			switch v {
+			case op.forced:
+				status = append(status, "fixed "+letter)
+			case op.preferred:
+				status = append(status, "preferred "+letter)
+			}
+		}
+		if len(status) == 0 {
+			status = append(status, "inferred")
+		}
+		fmt.Fprintf(&buf, "| %s | %s | %s | %s | %s |\n",
+			escape(op.name), scope, strings.Join(variants, "<br>"),
+			strings.Join(status, ", "), escape(op.doc))
+	}
+	_, err = ctxt.out.Write(buf.Bytes())
+	return err
+}
+
+// variantLetter returns the letter of the i-th operation variant,
+// like in the -force flag.
+func variantLetter(i int) string {
+	return string(rune('A' + i))
+}
+
+// referenceMatcher returns a function that reports whether the
+// file belongs to one of the -reference paths.
+// Returns nil function if -reference flag is not set.
+func (ctxt *context) referenceMatcher() (func(filename string) bool, error) {
+	var refs []string
+	for _, ref := range strings.Split(ctxt.flags.reference, ",") {
+		if ref = strings.TrimSpace(ref); ref == "" {
+			continue
+		}
+		abs, err := filepath.Abs(ref)
+		if err != nil {
+			return nil, fmt.Errorf("-reference: %v", err)
+		}
+		refs = append(refs, abs)
+	}
+	if len(refs) == 0 {
+		return nil, nil
+	}
+	return func(filename string) bool {
+		for _, ref := range refs {
+			if filename == ref || strings.HasPrefix(filename, ref+string(filepath.Separator)) {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
+
 func (ctxt *context) printWarnings() error {
-	exitCode := 0
-	visitWarings(ctxt, func(pos token.Position, v *opVariant) {
-		exitCode = 1
-		fmt.Printf("%s: %s: %s\n", pos, v.op.name, v.op.suggested.warning)
+	if ctxt.flags.countOnly {
+		visitWarings(ctxt, func(pos token.Position, v, suggested *opVariant) {
+			ctxt.countWarning(v)
+		})
+		if ctxt.flags.reportUnused {
+			ctxt.warnings += len(ctxt.unusedSuppressions())
+		}
+		_, err := fmt.Fprintln(ctxt.out, ctxt.warnings)
+		return err
+	}
+
+	if ctxt.flags.operations {
+		return ctxt.printViolatedOps()
+	}
+
+	var err error
+	if ctxt.flags.format == "jsonl" {
+		// Every warning is written as soon as it's visited,
+		// so the entire output is never buffered.
+		enc := json.NewEncoder(ctxt.out)
+		visitWarings(ctxt, func(pos token.Position, v, suggested *opVariant) {
+			ctxt.countWarning(v)
+			if err != nil {
+				return
+			}
+			err = enc.Encode(jsonWarning{
+				Filename: pos.Filename,
+				Line:     pos.Line,
+				Column:   pos.Column,
+				Op:       v.op.name,
+				Message:  jsonMessage(v, suggested) + ctxt.noteOf(pos),
+			})
+		})
+		return err
+	}
+
+	if ctxt.flags.groupBy == "operation" {
+		ctxt.printWarningsByOperation()
+		return nil
+	}
+
+	visitWarings(ctxt, func(pos token.Position, v, suggested *opVariant) {
+		ctxt.countWarning(v)
+		fmt.Fprintf(ctxt.out, "%s: %s%s\n", pos, warningText(v, suggested), ctxt.noteOf(pos))
+		if ctxt.flags.showSource {
+			ctxt.printSourceLine(pos)
+		}
+	})
+	return nil
+}
+
+// addNote attaches the note to the warning of the n candidate,
+// it's only printed if the candidate is reported.
+func (ctxt *context) addNote(n ast.Node, note string) {
+	if ctxt.notes == nil {
+		ctxt.notes = make(map[token.Position]string)
+	}
+	pos := ctxt.fset.Position(n.Pos())
+	// Positions of the candidate locations have no offsets.
+	pos.Offset = 0
+	ctxt.notes[pos] = note
+}
+
+// noteOf returns the suffix of the warning at pos with its note, if any.
+func (ctxt *context) noteOf(pos token.Position) string {
+	if note := ctxt.notes[pos]; note != "" {
+		return " (note: " + note + ")"
+	}
+	return ""
+}
+
+// jsonMessage returns the jsonWarning message: the suggestion,
+// or the entire warning text for operations with a message template.
+func jsonMessage(v, suggested *opVariant) string {
+	if suggested.op.message == nil {
+		return suggested.warning
+	}
+	return warningText(v, suggested)
+}
+
+// printWarningsByOperation prints warnings grouped by their suggested variants.
+// Every group starts with a header that describes the suggestion,
+// groups are ordered by their first warning.
+func (ctxt *context) printWarningsByOperation() {
+	var groups []*opVariant
+	positions := make(map[*opVariant][]token.Position)
+	visitWarings(ctxt, func(pos token.Position, v, suggested *opVariant) {
+		ctxt.countWarning(v)
+		if _, ok := positions[suggested]; !ok {
+			groups = append(groups, suggested)
+		}
+		positions[suggested] = append(positions[suggested], pos)
 	})
-	os.Exit(exitCode)
+
+	for _, suggested := range groups {
+		list := positions[suggested]
+		fmt.Fprintf(ctxt.out, "%s: %s (warnings: %d)\n",
+			suggested.op.name, suggested.warning, len(list))
+		for _, pos := range list {
+			fmt.Fprintf(ctxt.out, "\t%s%s\n", pos, ctxt.noteOf(pos))
+			if ctxt.flags.showSource {
+				ctxt.printSourceLine(pos)
+			}
+		}
+	}
+}
+
+// printSourceLine prints the source line of pos followed by
+// a line with a caret that points to the pos column.
+func (ctxt *context) printSourceLine(pos token.Position) {
+	if ctxt.sourceLines == nil {
+		ctxt.sourceLines = make(map[string][]string)
+	}
+	lines, ok := ctxt.sourceLines[pos.Filename]
+	if !ok {
+		data, err := ctxt.readSourceFile(pos.Filename)
+		if err != nil {
+			ctxt.infoPrintf("show source: %v", err)
+		}
+		lines = strings.Split(string(data), "\n")
+		ctxt.sourceLines[pos.Filename] = lines
+	}
+	if pos.Line < 1 || pos.Line > len(lines) {
+		return
+	}
+	line := strings.TrimSuffix(lines[pos.Line-1], "\r")
+	col := pos.Column - 1
+	if col < 0 || col > len(line) {
+		col = 0
+	}
+	// Keep the tabs, so the caret is aligned with the source line.
+	indent := []byte(line[:col])
+	for i, ch := range indent {
+		if ch != '\t' {
+			indent[i] = ' '
+		}
+	}
+	fmt.Fprintf(ctxt.out, "\t%s\n\t%s^\n", line, indent)
+}
+
+// countWarning records the warning reported for the v variant.
+func (ctxt *context) countWarning(v *opVariant) {
+	if ctxt.opWarnings == nil {
+		ctxt.opWarnings = make(map[string]int)
+	}
+	ctxt.warnings++
+	ctxt.opWarnings[v.op.name]++
+}
+
+func (ctxt *context) printSummary() error {
+	if !ctxt.flags.summary {
+		return nil
+	}
+	ctxt.logger.Printf("go-consistent: files=%d warnings=%d undecided=%d duration=%s",
+		ctxt.files, ctxt.warnings, ctxt.undecided, time.Since(ctxt.started).Round(time.Millisecond))
+
+	// Print per-operation breakdown, most frequently violated first.
+	for _, name := range ctxt.violatedOps() {
+		ctxt.logger.Printf("\t%s: %d", name, ctxt.opWarnings[name])
+	}
 	return nil
 }
 
-func visitWarings(ctxt *context, visit func(pos token.Position, v *opVariant)) {
+// violatedOps returns the names of operations that have warnings,
+// the most frequently violated first.
+func (ctxt *context) violatedOps() []string {
+	names := make([]string, 0, len(ctxt.opWarnings))
+	for name := range ctxt.opWarnings {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		x, y := ctxt.opWarnings[names[i]], ctxt.opWarnings[names[j]]
+		if x != y {
+			return x > y
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
+
+// printViolatedOps prints the -operations output.
+func (ctxt *context) printViolatedOps() error {
+	visitWarings(ctxt, func(pos token.Position, v, suggested *opVariant) {
+		ctxt.countWarning(v)
+	})
+	enc := json.NewEncoder(ctxt.out)
+	for _, name := range ctxt.violatedOps() {
+		var err error
+		if ctxt.flags.format == "jsonl" {
+			err = enc.Encode(jsonOpWarnings{Op: name, Warnings: ctxt.opWarnings[name]})
+		} else {
+			_, err = fmt.Fprintf(ctxt.out, "%s: %d\n", name, ctxt.opWarnings[name])
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkUndecided returns an error that lists all operations with ambiguous
+// suggestions if -fail-on-undecided is set.
+func (ctxt *context) checkUndecided() error {
+	if !ctxt.flags.failUndecided || len(ctxt.undecidedOps) == 0 {
+		return nil
+	}
+	return fmt.Errorf("undecided operations: %s", strings.Join(ctxt.undecidedOps, ", "))
+}
+
+func visitWarings(ctxt *context, visit func(pos token.Position, v, suggested *opVariant)) {
 	// Build variant map which is accessed by variantID.
 	vcount := 0
 	for _, c := range ctxt.checkers {
@@ -265,22 +1464,32 @@ func visitWarings(ctxt *context, visit func(pos token.Position, v *opVariant)) {
 
 	for _, c := range ctxt.candidates {
 		v := variants[c.variantID]
-		if v.op.suggested == v {
+		suggested := ctxt.suggestions[scopedOp{scopeID: c.scopeID, op: v.op}]
+		if suggested == nil || suggested == v {
 			continue // OK, everything is consistent
 		}
 		pos := ctxt.locs.Get(c.locationID)
-		visit(pos, v)
+		if !ctxt.isChangedLine(pos.Filename, pos.Line) {
+			continue
+		}
+		if !ctxt.isExportedPos(pos) {
+			continue
+		}
+		if ctxt.isSuppressed(pos, v.op) {
+			continue
+		}
+		visit(pos, v, suggested)
 	}
 }
 
 func (ctxt *context) debugPrintf(format string, args ...interface{}) {
 	if ctxt.flags.debug {
-		log.Printf("\tdebug: "+format, args...)
+		ctxt.logger.Printf("\tdebug: "+format, args...)
 	}
 }
 
 func (ctxt *context) infoPrintf(format string, args ...interface{}) {
 	if ctxt.flags.verbose {
-		log.Printf("\tinfo: "+format, args...)
+		ctxt.logger.Printf("\tinfo: "+format, args...)
 	}
 }
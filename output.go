@@ -0,0 +1,35 @@
+package main
+
+import (
+	"bufio"
+	"os"
+)
+
+// openOutput redirects the warnings output to the -out file, if it's set.
+// The file is created or truncated, it's written until closeOutput is called.
+func (ctxt *context) openOutput() error {
+	if ctxt.flags.out == "" {
+		return nil
+	}
+	f, err := os.Create(ctxt.flags.out)
+	if err != nil {
+		return err
+	}
+	ctxt.outFile = f
+	ctxt.outBuf = bufio.NewWriter(f)
+	ctxt.out = ctxt.outBuf
+	return nil
+}
+
+// closeOutput flushes and closes the -out file, if it was opened.
+func (ctxt *context) closeOutput() error {
+	if ctxt.outFile == nil {
+		return nil
+	}
+	err := ctxt.outBuf.Flush()
+	if closeErr := ctxt.outFile.Close(); err == nil {
+		err = closeErr
+	}
+	ctxt.outFile = nil
+	return err
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestOutputFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "go-consistent")
+	if err != nil {
+		t.Fatalf("create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	for _, format := range []string{"text", "jsonl"} {
+		filename := filepath.Join(dir, "warnings."+format)
+		// Existing contents are truncated.
+		if err := ioutil.WriteFile(filename, []byte("stale\n"), 0644); err != nil {
+			t.Fatalf("write %s: %v", filename, err)
+		}
+
+		ctxt := newTestContext(t)
+		ctxt.paths = []string{"./" + path.Join("testdata", "filter")}
+		ctxt.flags.out = filename
+		ctxt.flags.format = format
+		ctxt.flags.groupBy = "file"
+		if err := ctxt.openOutput(); err != nil {
+			t.Fatalf("%s: open output: %v", format, err)
+		}
+		runAnalysis(t, ctxt)
+		steps := []func() error{
+			ctxt.printWarnings,
+			ctxt.closeOutput,
+		}
+		for _, step := range steps {
+			if err := step(); err != nil {
+				t.Fatalf("%s: unexpected error: %v", format, err)
+			}
+		}
+
+		data, err := ioutil.ReadFile(filename)
+		if err != nil {
+			t.Fatalf("read %s: %v", filename, err)
+		}
+		lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+		if len(lines) != 1 {
+			t.Fatalf("%s: expected 1 warning line, got:\n%s", format, data)
+		}
+		if format == "text" {
+			if !strings.HasSuffix(lines[0], "empty map: use map[K]V{}") {
+				t.Errorf("text: unexpected warning: %s", lines[0])
+			}
+			continue
+		}
+		var w jsonWarning
+		if err := json.Unmarshal([]byte(lines[0]), &w); err != nil {
+			t.Fatalf("jsonl: decode warning: %v", err)
+		}
+		if w.Op != "empty map" {
+			t.Errorf("jsonl: unexpected warning: %+v", w)
+		}
+	}
+}
+
+func TestOutputFileError(t *testing.T) {
+	ctxt := newTestContext(t)
+	ctxt.flags.out = filepath.Join("testdata", "missing", "warnings.txt")
+	if err := ctxt.openOutput(); err == nil {
+		t.Errorf("expected an error for the missing directory")
+	}
+	if err := ctxt.closeOutput(); err != nil {
+		t.Errorf("close unopened output: %v", err)
+	}
+}
+
+func TestWatchOutputFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "go-consistent")
+	if err != nil {
+		t.Fatalf("create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	ctxt := newTestContext(t)
+	ctxt.flags.targets = []string{"./" + path.Join("testdata", "filter")}
+	ctxt.flags.exclude = `^unsafe$|^builtin$`
+	ctxt.flags.out = filepath.Join(dir, "warnings.txt")
+	ctxt.flags.format = "text"
+	ctxt.flags.groupBy = "file"
+	ctxt.flags.scope = "global"
+	ctxt.flags.chainDepth = 2
+	// Every run rewrites and flushes the file.
+	for i := 0; i < 2; i++ {
+		ctxt.watchRun()
+		data, err := ioutil.ReadFile(ctxt.flags.out)
+		if err != nil {
+			t.Fatalf("run %d: read output: %v", i, err)
+		}
+		if !strings.HasSuffix(string(data), "empty map: use map[K]V{}\n") || strings.Count(string(data), "\n") != 1 {
+			t.Errorf("run %d: unexpected output:\n%s", i, data)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// loadOverlay reads the -overlay file, if it's set.
+//
+// Overlay file is a JSON object that maps file names to their contents,
+// like `{"foo/bar.go": "package foo\n"}`. Relative file names are
+// resolved against the current directory.
+// The "-" overlay filename stands for the standard input.
+func (ctxt *context) loadOverlay() error {
+	var data []byte
+	var err error
+	switch ctxt.flags.overlay {
+	case "":
+		return nil
+	case "-":
+		data, err = ioutil.ReadAll(os.Stdin)
+	default:
+		data, err = ioutil.ReadFile(ctxt.flags.overlay)
+	}
+	if err != nil {
+		return err
+	}
+	var files map[string]string
+	if err := json.Unmarshal(data, &files); err != nil {
+		return fmt.Errorf("%s: %v", ctxt.flags.overlay, err)
+	}
+	ctxt.overlay = make(map[string][]byte, len(files))
+	for filename, contents := range files {
+		abs, err := filepath.Abs(filename)
+		if err != nil {
+			return err
+		}
+		ctxt.overlay[abs] = []byte(contents)
+	}
+	return nil
+}
+
+// readSourceFile returns the filename contents, preferring the -overlay version.
+// The in-memory files, like the -archive files, are never read from disk.
+func (ctxt *context) readSourceFile(filename string) ([]byte, error) {
+	if data, ok := ctxt.overlay[filename]; ok {
+		return data, nil
+	}
+	if data, ok := ctxt.sources[filename]; ok {
+		return data, nil
+	}
+	return ioutil.ReadFile(filename)
+}
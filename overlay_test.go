@@ -0,0 +1,42 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/token"
+	"path"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestOverlay(t *testing.T) {
+	dir := path.Join("testdata", "filter")
+	files := map[string]string{
+		// Replaces the only make(map) usage.
+		filepath.Join(dir, "b.go"): "package filter\n\nfunc makeCall() {\n\t_ = map[int]int{}\n}\n",
+		// Doesn't exist on disk.
+		filepath.Join(dir, "c.go"): "package filter\n\nfunc hexLits() {\n\t_ = 0xff\n\t_ = 0xfe\n\t_ = 0xFD\n}\n",
+	}
+	data, err := json.Marshal(files)
+	if err != nil {
+		t.Fatalf("encode overlay: %v", err)
+	}
+
+	ctxt := newTestContext(t)
+	ctxt.paths = []string{"./" + dir}
+	ctxt.flags.overlay = writeTestConfig(t, string(data))
+	if err := ctxt.loadOverlay(); err != nil {
+		t.Fatalf("load overlay: %v", err)
+	}
+	runAnalysis(t, ctxt)
+
+	var warnings []string
+	visitWarings(ctxt, func(pos token.Position, v, suggested *opVariant) {
+		warnings = append(warnings, fmt.Sprintf("%s:%d: %s", filepath.Base(pos.Filename), pos.Line, v.op.name))
+	})
+	want := []string{"c.go:6: hex lit"}
+	if !reflect.DeepEqual(warnings, want) {
+		t.Errorf("warnings mismatch:\nhave: %v\nwant: %v", warnings, want)
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"os"
+	"sort"
+	"time"
+)
+
+// recentWeightLevels is the max -prefer-recent file weight.
+const recentWeightLevels = 4
+
+// fileWeights returns the inference weights of the files
+// that have candidates, see -prefer-recent.
+// Returns nil if every candidate should be counted once.
+func (ctxt *context) fileWeights() map[string]int {
+	if !ctxt.flags.preferRecent {
+		return nil
+	}
+	mtimes := make(map[string]time.Time)
+	for _, c := range ctxt.candidates {
+		filename := ctxt.locs.Get(c.locationID).Filename
+		if _, ok := mtimes[filename]; ok {
+			continue
+		}
+		_, inOverlay := ctxt.overlay[filename]
+		_, inSources := ctxt.sources[filename]
+		if inOverlay || inSources {
+			// Unsaved and in-memory files are the most recent ones.
+			mtimes[filename] = ctxt.started
+			continue
+		}
+		info, err := os.Stat(filename)
+		if err != nil {
+			ctxt.infoPrintf("prefer recent: %v", err)
+			mtimes[filename] = time.Time{}
+			continue
+		}
+		mtimes[filename] = info.ModTime()
+	}
+	return recentWeights(mtimes)
+}
+
+// recentWeights maps filenames to their inference weights according
+// to their modification times: files are ordered from the oldest
+// to the newest and split into recentWeightLevels equal groups,
+// the oldest group files weigh 1 and the newest group files
+// weigh recentWeightLevels.
+// Files with equal modification times always have equal weights.
+func recentWeights(mtimes map[string]time.Time) map[string]int {
+	filenames := make([]string, 0, len(mtimes))
+	for filename := range mtimes {
+		filenames = append(filenames, filename)
+	}
+	sort.Slice(filenames, func(i, j int) bool {
+		return mtimes[filenames[i]].Before(mtimes[filenames[j]])
+	})
+	weights := make(map[string]int, len(filenames))
+	for i, filename := range filenames {
+		weights[filename] = 1 + recentWeightLevels*i/len(filenames)
+		if i != 0 && mtimes[filename].Equal(mtimes[filenames[i-1]]) {
+			weights[filename] = weights[filenames[i-1]]
+		}
+	}
+	return weights
+}
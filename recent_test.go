@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"go/token"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRecentWeights(t *testing.T) {
+	base := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	day := func(n int) time.Time { return base.AddDate(0, 0, n) }
+	tests := []struct {
+		mtimes map[string]time.Time
+		want   map[string]int
+	}{
+		{
+			mtimes: map[string]time.Time{"a": day(0)},
+			want:   map[string]int{"a": 1},
+		},
+		{
+			mtimes: map[string]time.Time{"a": day(0), "b": day(1)},
+			want:   map[string]int{"a": 1, "b": 3},
+		},
+		{
+			mtimes: map[string]time.Time{"a": day(3), "b": day(0), "c": day(2), "d": day(1)},
+			want:   map[string]int{"a": 4, "b": 1, "c": 3, "d": 2},
+		},
+		{
+			mtimes: map[string]time.Time{"a": day(0), "b": day(0), "c": day(1)},
+			want:   map[string]int{"a": 1, "b": 1, "c": 3},
+		},
+	}
+	for _, test := range tests {
+		have := recentWeights(test.mtimes)
+		if !reflect.DeepEqual(have, test.want) {
+			t.Errorf("recentWeights(%v):\nhave: %v\nwant: %v", test.mtimes, have, test.want)
+		}
+	}
+}
+
+func TestPreferRecent(t *testing.T) {
+	dir := path.Join("testdata", "recent")
+	now := time.Now()
+	mtimes := map[string]time.Time{
+		"old.go": now.AddDate(-1, 0, 0),
+		"new.go": now,
+	}
+	for name, mtime := range mtimes {
+		if err := os.Chtimes(filepath.Join(dir, name), mtime, mtime); err != nil {
+			t.Fatalf("set %s mtime: %v", name, err)
+		}
+	}
+
+	tests := []struct {
+		preferRecent bool
+		want         []string
+	}{
+		{false, []string{"new.go:4", "new.go:5"}},
+		{true, []string{"old.go:4", "old.go:5", "old.go:6"}},
+	}
+	for _, test := range tests {
+		ctxt := newTestContext(t)
+		ctxt.paths = []string{"./" + dir}
+		ctxt.flags.preferRecent = test.preferRecent
+		runAnalysis(t, ctxt)
+		var warnings []string
+		visitWarings(ctxt, func(pos token.Position, v, suggested *opVariant) {
+			warnings = append(warnings, fmt.Sprintf("%s:%d", filepath.Base(pos.Filename), pos.Line))
+		})
+		if !reflect.DeepEqual(warnings, test.want) {
+			t.Errorf("preferRecent=%v:\nhave: %v\nwant: %v", test.preferRecent, warnings, test.want)
+		}
+	}
+}
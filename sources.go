@@ -0,0 +1,116 @@
+package main
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path"
+	"path/filepath"
+	"sort"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// collectSourceCandidates checks the in-memory Go files,
+// sources maps file names to their contents.
+//
+// Every directory is checked as a separate package, files with
+// different package clauses, like external tests, are checked
+// as separate packages too.
+//
+// Unlike collectPathCandidates, it doesn't use the loader: only the
+// standard library packages are resolved, other imports are replaced
+// with empty packages, see stubImporter. Packages with type errors
+// are checked anyway, the expressions without types are not counted.
+func (ctxt *context) collectSourceCandidates(sources map[string][]byte) error {
+	if ctxt.sources == nil {
+		ctxt.sources = make(map[string][]byte, len(sources))
+	}
+	dirs := make(map[string][]string)
+	for filename, data := range sources {
+		ctxt.sources[filename] = data
+		dir := filepath.Dir(filename)
+		dirs[dir] = append(dirs[dir], filename)
+	}
+	dirList := make([]string, 0, len(dirs))
+	for dir := range dirs {
+		dirList = append(dirList, dir)
+	}
+	sort.Strings(dirList)
+
+	ctxt.fset = token.NewFileSet()
+	imp := stubImporter{importer.Default()}
+	for _, dir := range dirList {
+		if err := ctxt.collectSourceDirCandidates(imp, dir, dirs[dir]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (ctxt *context) collectSourceDirCandidates(imp types.Importer, dir string, filenames []string) error {
+	sort.Strings(filenames)
+	var names []string
+	pkgs := make(map[string]*packages.Package)
+	for _, filename := range filenames {
+		f, err := parser.ParseFile(ctxt.fset, filename, ctxt.sources[filename], parser.ParseComments)
+		if err != nil {
+			return err
+		}
+		pkg := pkgs[f.Name.Name]
+		if pkg == nil {
+			pkg = &packages.Package{
+				Name:    f.Name.Name,
+				PkgPath: dir,
+			}
+			pkgs[f.Name.Name] = pkg
+			names = append(names, f.Name.Name)
+		}
+		pkg.GoFiles = append(pkg.GoFiles, filename)
+		pkg.Syntax = append(pkg.Syntax, f)
+	}
+
+	for _, name := range names {
+		pkg := pkgs[name]
+		pkg.TypesInfo = &types.Info{
+			Types:      make(map[ast.Expr]types.TypeAndValue),
+			Defs:       make(map[*ast.Ident]types.Object),
+			Uses:       make(map[*ast.Ident]types.Object),
+			Implicits:  make(map[ast.Node]types.Object),
+			Selections: make(map[*ast.SelectorExpr]*types.Selection),
+			Scopes:     make(map[ast.Node]*types.Scope),
+		}
+		var typeErrs []error
+		conf := types.Config{
+			Importer: imp,
+			Error: func(err error) {
+				typeErrs = append(typeErrs, err)
+			},
+		}
+		conf.Check(dir, ctxt.fset, pkg.Syntax, pkg.TypesInfo)
+		if len(typeErrs) != 0 {
+			ctxt.infoPrintf("%s: %s package: %d type errors, first: %v",
+				dir, name, len(typeErrs), typeErrs[0])
+		}
+		ctxt.collectPackageCandidates(pkg)
+	}
+	return nil
+}
+
+// stubImporter imports the packages with the wrapped importer and
+// returns the empty packages for the ones it can't import, so the
+// rest of the importing package is still type-checked.
+type stubImporter struct {
+	types.Importer
+}
+
+func (imp stubImporter) Import(pkgPath string) (*types.Package, error) {
+	if pkg, err := imp.Importer.Import(pkgPath); err == nil {
+		return pkg, nil
+	}
+	pkg := types.NewPackage(pkgPath, path.Base(pkgPath))
+	pkg.MarkComplete()
+	return pkg, nil
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+	"go/token"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSourceCandidates(t *testing.T) {
+	// Files don't exist on disk, so they can't be read by accident.
+	dir := filepath.Join(string(filepath.Separator), "nonexistent", "go-consistent")
+	sources := map[string][]byte{
+		filepath.Join(dir, "foo", "a.go"): []byte("package foo\n\nfunc emptyMaps() {\n\t_ = make(map[int]int)\n\t_ = make(map[int]int)\n\t_, _ = 1, map[int]int{}\n}\n"),
+		filepath.Join(dir, "foo", "b.go"): []byte("package foo\n\nimport \"strings\"\n\nvar _ = strings.ToUpper\n"),
+	}
+
+	ctxt := newTestContext(t)
+	if err := ctxt.initCheckers(); err != nil {
+		t.Fatalf("init checkers: %v", err)
+	}
+	if err := ctxt.collectSourceCandidates(sources); err != nil {
+		t.Fatalf("collect candidates: %v", err)
+	}
+	if err := ctxt.assignSuggestions(); err != nil {
+		t.Fatalf("assign suggestions: %v", err)
+	}
+
+	var warnings []string
+	visitWarings(ctxt, func(pos token.Position, v, suggested *opVariant) {
+		warnings = append(warnings, fmt.Sprintf("%s: %s", pos, v.op.name))
+	})
+	want := []string{
+		filepath.Join(dir, "foo", "a.go") + ":6:12: empty map",
+	}
+	if !reflect.DeepEqual(warnings, want) {
+		t.Errorf("warnings mismatch:\nhave: %v\nwant: %v", warnings, want)
+	}
+
+	data, err := ctxt.readSourceFile(filepath.Join(dir, "foo", "b.go"))
+	if err != nil {
+		t.Fatalf("read source: %v", err)
+	}
+	if string(data) != string(sources[filepath.Join(dir, "foo", "b.go")]) {
+		t.Errorf("read source: unexpected contents %q", data)
+	}
+}
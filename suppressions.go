@@ -0,0 +1,147 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"strings"
+)
+
+// ignoreDirective is a comment prefix that suppresses warnings
+// on its own line and on the line that follows it.
+//
+// It can be followed by a comma-separated list of operation names,
+// like in `//go-consistent:ignore empty map, hex lit`;
+// without the list, all operations are suppressed.
+const ignoreDirective = "//go-consistent:ignore"
+
+type suppression struct {
+	pos token.Position
+
+	// ops is a list of suppressed operation names.
+	// Empty list means that all operations are suppressed.
+	ops []string
+
+	// used reports whether the suppression hid at least one warning.
+	//
+	// Updated by the context.isSuppressed.
+	used bool
+}
+
+// suppressedLine is a key of the context.suppressions map.
+type suppressedLine struct {
+	filename string
+	line     int
+}
+
+func (s *suppression) matches(op *operation) bool {
+	if len(s.ops) == 0 {
+		return true
+	}
+	for _, name := range s.ops {
+		if name == op.name {
+			return true
+		}
+	}
+	return false
+}
+
+// collectSuppressions records all ignore directives of the f file.
+func (ctxt *context) collectSuppressions(f *ast.File) {
+	for _, group := range f.Comments {
+		for _, comment := range group.List {
+			if !strings.HasPrefix(comment.Text, ignoreDirective) {
+				continue
+			}
+			rest := comment.Text[len(ignoreDirective):]
+			if rest != "" && rest[0] != ' ' && rest[0] != '\t' {
+				continue // Some other directive, like //go-consistent:ignored
+			}
+			pos := ctxt.fset.Position(comment.Pos())
+			if ctxt.hasSuppression(pos) {
+				continue // Already collected from another package variant
+			}
+			s := &suppression{pos: pos}
+			for _, name := range strings.Split(rest, ",") {
+				if name = strings.TrimSpace(name); name != "" {
+					s.ops = append(s.ops, name)
+				}
+			}
+			if ctxt.suppressions == nil {
+				ctxt.suppressions = make(map[suppressedLine][]*suppression)
+			}
+			ctxt.suppressionList = append(ctxt.suppressionList, s)
+			for _, line := range []int{pos.Line, pos.Line + 1} {
+				key := suppressedLine{filename: pos.Filename, line: line}
+				ctxt.suppressions[key] = append(ctxt.suppressions[key], s)
+			}
+		}
+	}
+}
+
+func (ctxt *context) hasSuppression(pos token.Position) bool {
+	for _, s := range ctxt.suppressions[suppressedLine{filename: pos.Filename, line: pos.Line}] {
+		if s.pos == pos {
+			return true
+		}
+	}
+	return false
+}
+
+// isSuppressed reports whether a warning of the op operation at pos
+// is hidden by some ignore directive.
+// All matching directives are marked as used.
+func (ctxt *context) isSuppressed(pos token.Position, op *operation) bool {
+	suppressed := false
+	for _, s := range ctxt.suppressions[suppressedLine{filename: pos.Filename, line: pos.Line}] {
+		if s.matches(op) {
+			s.used = true
+			suppressed = true
+		}
+	}
+	return suppressed
+}
+
+// unusedSuppressions returns all ignore directives that didn't hide any warnings,
+// except those outside of the -include-only-changed-lines patch.
+// Only valid after all warnings are visited.
+func (ctxt *context) unusedSuppressions() []*suppression {
+	var list []*suppression
+	for _, s := range ctxt.suppressionList {
+		if !s.used && ctxt.isChangedLine(s.pos.Filename, s.pos.Line) {
+			list = append(list, s)
+		}
+	}
+	return list
+}
+
+// reportUnusedSuppressions prints unused ignore directives
+// if -report-unused-suppressions is set.
+// Every reported directive is counted as a warning.
+func (ctxt *context) reportUnusedSuppressions() error {
+	if !ctxt.flags.reportUnused || ctxt.flags.countOnly {
+		// For -count-only, they're counted by the context.printWarnings.
+		return nil
+	}
+	enc := json.NewEncoder(ctxt.out)
+	for _, s := range ctxt.unusedSuppressions() {
+		ctxt.warnings++
+		const message = "unused suppression"
+		if ctxt.flags.format == "jsonl" {
+			err := enc.Encode(jsonWarning{
+				Filename: s.pos.Filename,
+				Line:     s.pos.Line,
+				Column:   s.pos.Column,
+				Op:       "suppression",
+				Message:  message,
+			})
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		fmt.Fprintf(ctxt.out, "%s: %s\n", s.pos, message)
+	}
+	return nil
+}
@@ -0,0 +1,5 @@
+package aliasnames
+
+import str "strconv"
+
+var _ = str.Quote
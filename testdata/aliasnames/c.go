@@ -0,0 +1,5 @@
+package aliasnames
+
+import conv "strconv"
+
+var _ = conv.Atoi
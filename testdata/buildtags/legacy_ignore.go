@@ -0,0 +1,5 @@
+// +build ignore
+
+package buildtags
+
+func legacyIgnore() {}
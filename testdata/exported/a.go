@@ -0,0 +1,43 @@
+package exported
+
+func unexported() {
+	_ = map[int]int{}
+}
+
+func Exported() {
+	_ = map[int]int{}
+	_ = func() {
+		_ = map[int]int{}
+	}
+}
+
+type T struct{}
+
+func (T) Method() { _ = map[int]int{} }
+
+func (T) Other() { _ = map[int]int{} }
+
+func (T) method() { _ = map[int]int{} }
+
+type t struct{}
+
+func (t) Method() { _ = map[int]int{} }
+
+var Table = map[string]int{}
+
+var table = map[string]int{}
+
+var (
+	Names = map[string]int{}
+	names = map[string]int{}
+)
+
+type Config struct {
+	Limits map[string]int
+}
+
+type config struct {
+	Limits map[string]int
+}
+
+var _, Registry = map[string]int{}, map[string]int{}
@@ -0,0 +1,6 @@
+package filter
+
+func literals() {
+	_ = map[int]int{}
+	_ = map[string]int{}
+}
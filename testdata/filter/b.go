@@ -0,0 +1,5 @@
+package filter
+
+func makeCall() {
+	_ = make(map[int]int)
+}
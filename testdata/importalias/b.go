@@ -0,0 +1,10 @@
+package importalias
+
+import (
+	"strings"
+
+	str "strconv"
+)
+
+var _ = strings.ToLower
+var _ = str.Quote
@@ -0,0 +1,9 @@
+package importalias
+
+import (
+	"strconv"
+	s "strings"
+)
+
+var _ = s.TrimSpace
+var _ = strconv.Atoi
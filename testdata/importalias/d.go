@@ -0,0 +1,10 @@
+package importalias
+
+import (
+	_ "errors"
+	. "fmt"
+	strings "strings"
+)
+
+var _ = strings.Fields
+var _ = Sprint
@@ -0,0 +1,14 @@
+package logger
+
+import (
+	"log"
+
+	"github.com/Quasilyte/go-consistent/testdata/logger/zap"
+)
+
+func logCalls(l *zap.Logger, err error) {
+	log.Printf("failed: %v", err)
+	log.Printf("failed again: %v", err)
+	l.Error("failed", zap.Error(err))
+	_ = zap.ErrorOutput(nil)
+}
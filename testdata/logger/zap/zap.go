@@ -0,0 +1,18 @@
+// Package zap mimics the go.uber.org/zap API for the logger check tests.
+package zap
+
+type Field struct{}
+
+// Error is a field constructor, not a logging call.
+func Error(err error) Field { return Field{} }
+
+// ErrorOutput is an option constructor, not a logging call.
+func ErrorOutput(w interface{}) Field { return Field{} }
+
+type Logger struct{}
+
+func (l *Logger) Error(msg string, fields ...Field) {}
+
+type SugaredLogger struct{}
+
+func (l *SugaredLogger) Infow(msg string, kv ...interface{}) {}
@@ -0,0 +1,11 @@
+package loopvars
+
+func use(int) {}
+
+func f(xs []int) {
+	for _, x := range xs {
+		go func(x int) { use(x) }(x)
+		go func(x int) { use(x) }(x)
+		go func() { use(x) }()
+	}
+}
@@ -0,0 +1,3 @@
+module example.com/loopvars/new
+
+go 1.22
@@ -0,0 +1,3 @@
+module example.com/loopvars/old
+
+go 1.12
@@ -0,0 +1,6 @@
+package a
+
+var (
+	_ = 0xaa
+	_ = 0xbb
+)
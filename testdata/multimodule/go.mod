@@ -0,0 +1,3 @@
+module example.com/multimodule
+
+go 1.12
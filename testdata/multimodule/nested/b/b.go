@@ -0,0 +1,5 @@
+package b
+
+import "example.com/nested/c"
+
+var _ = c.Value + 0xCC
@@ -0,0 +1,4 @@
+package c
+
+// Value is imported by the b package.
+const Value = 0
@@ -5,11 +5,13 @@ package ntests1
 import "strconv"
 import "errors"
 import "fmt"
+import "time"
 
 var (
 	_ = fmt.Printf
 	_ = errors.New
 	_ = strconv.Atoi
+	_ = time.Now
 )
 
 // T is an example type.
@@ -57,6 +59,10 @@ func floatLit() {
 	_ = 0.0
 	_ = 0.123
 	_ = 1.0
+
+	// Can't be spelled with implicit int/frac part:
+	_ = 1e5
+	_ = 0x1p-2
 }
 
 func labelCase() {
@@ -130,3 +136,71 @@ func defaultCaseOrder(x int, v interface{}) {
 	case x > 20:
 	}
 }
+
+func typeAssert(x interface{}) {
+	v1, ok := x.(int)
+	var v2, ok2 = x.(string)
+	_, _, _, _ = v1, v2, ok, ok2
+}
+
+func scalarPtrAlloc() {
+	_ = new(int)
+	_ = new(string)
+
+	// Not a scalar pointer allocation:
+	t := T{}
+	pt := &t
+	_ = pt
+
+	// Not a zero value allocation:
+	x := 10
+	px := &x
+	_ = px
+
+	// Not a builtin new:
+	{
+		new := func(int) *int { return nil }
+		_ = new(0)
+	}
+}
+
+func durationLit(n int) {
+	_ = 5 * time.Second
+	_ = 10 * time.Minute
+	_ = time.Duration(n) * time.Hour
+
+	// Not a duration.
+	_ = 5 * n
+}
+
+func convSpelling(s string, x int, bs []byte) {
+	_ = []byte(s)
+	_ = []rune(s)
+	_ = rune('b')
+
+	// Not checked: numeric and non-string conversions.
+	_ = uint8(x)
+	_ = int32(x)
+	_ = []uint8(bs)
+}
+
+type receiverKind struct{ x int }
+
+func (r *receiverKind) A() int { return r.x }
+func (r *receiverKind) B() int { return r.x }
+
+type otherReceiverKind struct{}
+
+func (otherReceiverKind) A() {}
+
+func errorString(err error, msg string) error {
+	_ = errors.New("not found")
+	_ = fmt.Errorf("load config: %v", err)
+
+	// Acronyms and identifiers:
+	_ = errors.New("HTTP request failed")
+	_ = errors.New("Config.Load failed")
+	// Not a literal:
+	_ = errors.New(msg)
+	return nil
+}
@@ -14,10 +14,15 @@ import (
 	"fmt"
 )
 
+import (
+	"time"
+)
+
 var (
 	_ = fmt.Printf
 	_ = errors.New
 	_ = strconv.Atoi
+	_ = time.Now
 )
 
 // T is an example type.
@@ -144,3 +149,49 @@ func defaultCaseOrder(x int, v interface{}) {
 	default:
 	}
 }
+
+func typeAssert(x interface{}) {
+	v1 := x.(int)
+	var v2 = x.(string)
+	_, _ = v1, v2
+
+	// Type switches are not type assertions.
+	switch v := x.(type) {
+	case int:
+		_ = v
+	}
+	switch y := x.(type) {
+	case int:
+		_ = y
+	}
+}
+
+func scalarPtrAlloc() {
+	x := 0
+	p := &x
+	_ = p
+	if p != nil {
+		s := ""
+		ps := &s
+		_ = ps
+	}
+}
+
+func durationLit() {
+	_ = time.Duration(5) * time.Second
+	_ = time.Duration(10) * time.Minute
+}
+
+func convSpelling(s string, x int) {
+	_ = []uint8(s)
+	_ = int32('b')
+
+	// Not checked: numeric conversions.
+	_ = byte(x)
+	_ = rune(x)
+}
+
+type receiverKind struct{ x int }
+
+func (r receiverKind) A() int { return r.x }
+func (r receiverKind) B() int { return r.x }
@@ -0,0 +1,606 @@
+package pntests1
+
+// In this test suite, (1) option is always used. No warnings should be generated.
+// Only pedantic checks are tested here.
+
+import "bufio"
+
+import "context"
+
+import "errors"
+
+import "fmt"
+
+import "log"
+
+import "log/slog"
+
+import "os"
+
+import "reflect"
+
+import "sync"
+
+import (
+	"strings"
+
+	_ "errors"
+	_ "io"
+)
+
+// Blank imports only:
+import (
+	_ "os"
+	_ "sort"
+)
+
+import (
+	"path"
+
+	// Comments are a part of the group:
+	e2e1 "github.com/Quasilyte/go-consistent/internal/end2end"
+)
+
+// Standard library imports only:
+import (
+	"io/ioutil"
+	"unicode"
+)
+
+func sliceConcat(a, b []int) {
+	a = append(a, b...)
+	b = append(b, a...)
+
+	// Not a concatenation:
+	copy(a, b)
+	copy(a[1:], b)
+	_ = append(b, a...)
+}
+
+func mapIndex(m map[string]int) {
+	v1, ok := m["a"]
+	var v2, ok2 = m["b"]
+	_, _, _, _ = v1, v2, ok, ok2
+}
+
+type myError struct{}
+
+func (*myError) Error() string { return "" }
+
+func typedNilReturn(cond bool) error {
+	if cond {
+		return nil
+	}
+
+	// Assigned vars are not guaranteed to be nil.
+	var err *myError
+	if !cond {
+		err = &myError{}
+	}
+	if cond {
+		return err
+	}
+
+	// Not an interface result.
+	_ = func() *myError {
+		var err *myError
+		return err
+	}
+
+	return nil
+}
+
+func ifInit(f func() (int, error)) {
+	if v, err := f(); err != nil {
+		_ = v
+	}
+
+	// Can't be moved into if init, since v is used after the if.
+	v, err := f()
+	if err != nil {
+		return
+	}
+	_ = v
+
+	// Not a declaration.
+	v, err = f()
+	if err != nil {
+		return
+	}
+}
+
+func sliceClone(src []int) {
+	dst1 := append([]int(nil), src...)
+	dst2 := append([]int{}, src...)
+	_, _ = dst1, dst2
+}
+
+func rangeValue(xs, ys []int, m map[int]int) {
+	for i := range xs {
+		println(xs[i])
+	}
+
+	// First key usage is not an index expression of the ranged slice:
+	for i := range xs {
+		println(i, xs[i])
+	}
+	for i := range xs {
+		println(ys[i])
+	}
+
+	// Not a slice:
+	for k, v := range m {
+		println(k, v)
+	}
+}
+
+func chanDrain(ch <-chan int, xs []int) {
+	for range ch {
+	}
+
+	// Received values are used:
+	for x := range ch {
+		println(x)
+	}
+	for {
+		x, ok := <-ch
+		if !ok {
+			break
+		}
+		println(x)
+	}
+
+	// Not a channel:
+	for range xs {
+	}
+}
+
+func printlnCalls(x int) {
+	fmt.Println("hello")
+
+	// Not equivalent to a Println call:
+	fmt.Printf("%d\n", x)
+	fmt.Printf("100%%\n")
+	fmt.Printf("no newline")
+	fmt.Println("x", x)
+}
+
+// Not checked: returns values, ends with panic, loop or conditional.
+
+func voidReturn1(x int) int {
+	return x
+}
+
+func voidReturn2() {
+	panic("unreachable")
+}
+
+func voidReturn3(ch <-chan int) {
+	for {
+		println(<-ch)
+	}
+}
+
+func voidReturn4(x int) {
+	if x != 0 {
+		println(x)
+		return
+	}
+}
+
+func blankImport() {
+	_ = strings.ToUpper
+}
+
+type chainBuilder struct{}
+
+func (b *chainBuilder) A() *chainBuilder { return b }
+func (b *chainBuilder) B() *chainBuilder { return b }
+func (b *chainBuilder) C() *chainBuilder { return b }
+
+func methodChain(x *chainBuilder) {
+	_ = x.A().B().C()
+
+	// Not deep enough:
+	_ = x.A().B()
+	a := x.A()
+	b := a.B()
+	_ = b
+
+	// Receivers are not from the previous statement:
+	c := x.A()
+	d := x.B()
+	e := c.C()
+	_, _ = d, e
+}
+
+func fieldsSplit(s, sep string) {
+	_ = strings.Fields(s)
+
+	// Not a whitespace separator:
+	_ = strings.Split(s, ",")
+	_ = strings.Split(s, "  ")
+	_ = strings.Split(s, sep)
+}
+
+func sliceDelete(xs, ys []int, i int) {
+	xs = append(xs[:i], xs[i+1:]...)
+
+	// Not a deletion:
+	xs = append(xs[:i], xs[i+2:]...)
+	xs = append(ys[:i], xs[i+1:]...)
+	xs = append(xs[:i], xs[i+1])
+}
+
+func mapInit(k string) {
+	m1 := map[string]int{"a": 1}
+
+	// Not an initialization with known keys:
+	m2 := make(map[string]int)
+	m2[k] = 1
+	m3 := make(map[string]int)
+	m1["b"] = 2
+	_, _ = m2, m3
+}
+
+func sliceInit(a int, xs []int) {
+	s1 := []int{a}
+
+	// Not an initialization with known elements:
+	var s2 []int
+	s2 = append(s2, xs...)
+	var s3 []int
+	s1 = append(s1, a)
+	_, _ = s2, s3
+}
+
+func deferClose1(filename string) error {
+	f, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	println(f.Name())
+	return nil
+}
+
+// Not checked: Close is deferred later or never called.
+
+func deferClose2(filename string) {
+	f, _ := os.Open(filename)
+	println(f.Name())
+	defer f.Close()
+}
+
+func deferClose3(filename string) *os.File {
+	f, _ := os.Open(filename)
+	return f
+}
+
+func lineReader(f *os.File) {
+	_ = bufio.NewScanner(f)
+
+	// Not a line reading constructor:
+	_ = bufio.NewReaderSize(f, 1024)
+	_ = bufio.NewWriter(f)
+}
+
+func errScope1(f func() error) error {
+	if err := f(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Every function is inferred separately.
+func errScope2(f func() error) error {
+	err := f()
+	if err != nil {
+		return err
+	}
+	err = f()
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+var ErrCompare error = errors.New("compare")
+
+func errCompare(err error) bool {
+	_ = err == ErrCompare
+
+	// Not a sentinel error comparison:
+	_ = err == nil
+	errLocal := errors.New("local")
+	_ = err == errLocal
+	return err != ErrCompare
+}
+
+func nilCheck(p *int, fn func(), iface interface{}) {
+	_ = p == nil
+	_ = fn != nil
+
+	// Interfaces can hold typed nil values:
+	_ = reflect.ValueOf(iface).IsNil()
+	_ = iface == nil
+}
+
+func stringBuilder(prefix, name string) string {
+	var sb strings.Builder
+	sb.WriteString(prefix)
+	sb.WriteString(name)
+	return sb.String()
+}
+
+type stringerValue int
+
+func (stringerValue) String() string { return "" }
+
+type stringerError int
+
+func (stringerError) String() string { return "" }
+func (stringerError) Error() string  { return "" }
+
+func fmtStringer(x stringerValue, e stringerError, iface fmt.Stringer) {
+	_ = fmt.Sprintf("%s", x)
+	_ = fmt.Sprintf("100%% %v", x)
+
+	// fmt would use Error method instead:
+	_ = fmt.Sprintf("%s", e.String())
+	// Dynamic type can implement error:
+	_ = fmt.Sprintf("%s", iface.String())
+}
+
+func multiAssign(x, y int) {
+	a, b := 1, "b"
+
+	// Swaps and dependent assignments:
+	x, y = y, x
+	c := x
+	d := c
+	// Not simple values:
+	e := x + 1
+	f := y + 1
+	_, _, _, _, _, _ = a, b, c, d, e, f
+}
+
+func envLookup() string {
+	home, ok := os.LookupEnv("HOME")
+	if !ok {
+		home = "/"
+	}
+
+	// Not an emptiness check:
+	term := os.Getenv("TERM")
+	println(term)
+	return home
+}
+
+func doubleNegation(disabled, noCache, nothing bool, noValues []int) {
+	if disabled || noCache {
+		return
+	}
+	if !nothing {
+	}
+	// Not booleans:
+	if noValues == nil {
+	}
+}
+
+type errResult struct{ n int }
+
+func errReturn(f func() (int, error)) (*errResult, int, error) {
+	n, err := f()
+	if err != nil {
+		return nil, 0, err
+	}
+	return &errResult{}, n, nil
+}
+
+// Inferred separately from errReturn returns.
+func errReturnVars(f func() (int, error)) (p *errResult, n int, err error) {
+	var zero int
+	_, err = f()
+	if err != nil {
+		return p, zero, err
+	}
+	// Assigned variables are not zero values:
+	var x int
+	x, err = f()
+	if err != nil {
+		return p, x, err
+	}
+	n, err = f()
+	if err != nil {
+		return p, n, err
+	}
+	_ = func() (int, error) {
+		if err != nil {
+			return 0, err
+		}
+		return 0, nil
+	}
+	return p, n, nil
+}
+
+func chanDir(in <-chan int, out chan<- int, errs chan error) {
+	for x := range in {
+		out <- x
+	}
+	// Used both ways:
+	errs <- nil
+	<-errs
+}
+
+func redundantParens(x int, p *int) int {
+	_ = (x + 1) * 2
+	// Only single identifiers and literals are checked:
+	_ = (*p)
+	_ = (*int)(p)
+	return x
+}
+
+const (
+	iotaA = iota
+	iotaB
+	// Another expression, repeated implicitly:
+	iotaC = iota * 10
+	iotaD = iota
+)
+
+type iotaKind int
+
+const (
+	kindA iotaKind = iota
+	kindB
+	// Untyped, not a repetition:
+	kindC = iota
+	kindD
+)
+
+// Doesn't use iota:
+const (
+	constA = 1
+	constB = 1
+)
+
+type structTags struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// Most of the fields are untagged:
+type structTagsHalf struct {
+	ID    int `json:"id"`
+	Name  string
+	Email string
+}
+
+type structTagsNone struct {
+	ID   int
+	Name string
+}
+
+var stdImportGroup = []interface{}{path.Join, ioutil.ReadAll, unicode.IsUpper, e2e1.ParseTestFile}
+
+type structVerbPoint struct{ X, Y int }
+
+func (structVerbPoint) Error() string { return "" }
+
+type structVerbPlain struct{ X, Y int }
+
+func structVerb(p structVerbPoint, plain structVerbPlain) {
+	_ = fmt.Sprintf("point: %v", plain)
+
+	// Implements error:
+	_ = fmt.Sprintf("point: %+v %#v", p, p)
+	// Not a format argument:
+	_ = fmt.Sprint(plain)
+}
+
+const (
+	constBaseA = 10
+	constBaseB = 20
+	// Not a literal:
+	constBaseC = constBaseA << 4
+)
+
+const (
+	constFlagNone = 0
+	constFlagA    = 0x1
+	constFlagB    = 0x2
+)
+
+func ctxParamFirst(ctx context.Context, x int) {}
+
+// No context parameters:
+func ctxParamNone(x int) {}
+
+func sliceBounds(s []int, str string, arr [4]int, i int, f func() []int) {
+	_ = s[:]
+	_ = s[i:]
+	_ = str[:i]
+	_ = s[1:]
+	// Length of other slice:
+	_ = s[:len(str)]
+	// Required in 3-index slices:
+	_ = s[:len(s):len(s)]
+	// The call result may differ:
+	_ = f()[:len(f())]
+}
+
+func goroutineArgs(xs []int, done chan<- bool) {
+	go func(xs []int) { _ = xs }(xs)
+	go func(done chan<- bool) { done <- true }(done)
+	// Nothing is captured:
+	go func() {
+		n := 0
+		_ = n
+	}()
+	// Not a func literal:
+	go close(done)
+}
+
+func deferFunc(mu *sync.Mutex, cleanup func()) {
+	mu.Lock()
+	defer mu.Unlock()
+	defer cleanup()
+}
+
+func deferFuncLit(cleanup func()) {
+	defer func() { cleanup() }()
+	defer func() { recover() }()
+}
+
+func logger(l *slog.Logger) {
+	slog.Info("started", "port", 80)
+	l.Warn("slow")
+	// Not a logging call:
+	log.SetPrefix("app: ")
+}
+
+func slogMsg(ctx context.Context, l *slog.Logger, port int) {
+	slog.Info("started", "port", port)
+	l.InfoContext(ctx, "started", "port", port)
+	// Formatted attribute values:
+	l.Info("started", "addr", fmt.Sprintf(":%d", port))
+	// Not a logging call:
+	_ = slog.String("addr", fmt.Sprintf(":%d", port))
+}
+
+type ctorPoint struct{ X, Y int }
+
+type ctorBuffer struct{ data [64]byte }
+
+type ctorID int
+
+func NewPoint(x, y int) ctorPoint { return ctorPoint{X: x, Y: y} }
+
+// Not a small struct:
+func NewBuffer() *ctorBuffer { return &ctorBuffer{} }
+
+// Not a struct:
+func NewID() *ctorID { return nil }
+
+// Defined in other package:
+func NewReader() *strings.Reader { return strings.NewReader("") }
+
+// Methods are not constructors:
+func (ctorPoint) NewPoint() *ctorPoint { return &ctorPoint{} }
+
+func sizedSliceMake(n int) {
+	_ = make([]int, n)
+	_ = make([]string, 10)
+
+	// Not a slice make with known size:
+	_ = make([]int, n, n*2)
+	_ = make(chan int, n)
+}
+
+func sizedSliceMakeShadowed(n int) {
+	make := func(typ []int, args ...int) []int { return typ }
+	// Not a builtin make:
+	_ = make([]int{}, 0, n)
+	_ = make([]int{})
+}
@@ -0,0 +1,314 @@
+package pntests2
+
+// In this test suite, (2) option is always used. No warnings should be generated.
+// Only pedantic checks are tested here.
+
+import "bufio"
+
+import "errors"
+
+import "fmt"
+
+import "log"
+
+import "log/slog"
+
+import "os"
+
+import "sync"
+
+import (
+	"strings"
+	_ "errors"
+
+	"bytes"
+	_ "io"
+)
+
+import (
+	"path"
+	e2e1 "github.com/Quasilyte/go-consistent/internal/end2end"
+)
+
+func sliceConcat(a, b []int) {
+	copy(a[len(b):], b)
+	copy(b[len(a):], a)
+
+	// Not a concatenation:
+	a = append(a, 1, 2)
+	a = append(b, a...)
+}
+
+type stringMap map[string]string
+
+func mapIndex(m map[string]int, sm stringMap) {
+	v1 := m["a"]
+	var v2 = sm["b"]
+	_, _ = v1, v2
+
+	// Map assignments are not reads.
+	m["a"] = 1
+	m["b"], m["c"] = 2, 3
+}
+
+func ifInit(f func() (int, error)) {
+	v, err := f()
+	if err != nil {
+		_ = v
+	}
+
+	// Not a declaration.
+	var e error
+	if e = g(); e != nil {
+	}
+
+	// x is not used in the condition.
+	x := 10
+	if v == 0 {
+		_ = x
+	}
+}
+
+func g() error { return nil }
+
+func sliceClone(src, other []int) {
+	dst1 := make([]int, len(src))
+	copy(dst1, src)
+
+	// Not a clone:
+	dst2 := make([]int, len(src))
+	copy(dst2, other)
+	dst3 := append([]int{1}, src...)
+	_ = dst3
+}
+
+func rangeValue(xs []int) {
+	for i, x := range xs {
+		println(i, x)
+	}
+	for _, x := range xs {
+		println(x)
+	}
+
+	// Value is not bound:
+	for i, _ := range xs {
+		println(i)
+	}
+}
+
+func chanDrain(ch chan int) {
+	for {
+		<-ch
+	}
+
+	// Loop body does more than receiving:
+	for {
+		<-ch
+		println("received")
+	}
+}
+
+func printlnCalls(x int) {
+	fmt.Printf("hello\n")
+
+	// Not equivalent to a Println call:
+	fmt.Printf("%d\n", x)
+	fmt.Print("hello\n")
+	fmt.Println(x)
+}
+
+func blankImport() {
+	_ = strings.ToUpper
+	_ = bytes.ToUpper
+}
+
+type chainBuilder struct{}
+
+func (b *chainBuilder) A() *chainBuilder { return b }
+func (b *chainBuilder) B() *chainBuilder { return b }
+func (b *chainBuilder) C() *chainBuilder { return b }
+
+func methodChain(x *chainBuilder) {
+	a := x.A()
+	b := a.B()
+	c := b.C()
+	_ = c
+
+	// Not deep enough:
+	_ = x.A().B()
+}
+
+func fieldsSplit(s string) {
+	_ = strings.Split(s, " ")
+	_ = strings.Split(s, `	`)
+
+	// Not a whitespace separator:
+	_ = strings.Split(s, ", ")
+	_ = strings.SplitN(s, " ", 2)
+}
+
+func sliceDelete(xs, ys []int, i int) {
+	for j := i; j < len(xs)-1; j++ {
+		xs[j] = xs[j+1]
+	}
+	xs = xs[:len(xs)-1]
+
+	// Not a deletion, slice is not truncated:
+	for j := i; j < len(xs)-1; j++ {
+		xs[j] = xs[j+1]
+	}
+	println(len(xs))
+}
+
+func mapInit(k string) {
+	m1 := make(map[string]int)
+	m1["a"] = 1
+
+	// Not an initialization with known keys:
+	m2 := make(map[string]int)
+	m2[k] = 1
+	_ = m2
+}
+
+func sliceInit(a int, xs []int) {
+	var s1 []int
+	s1 = append(s1, a)
+
+	// Not an initialization with known elements:
+	var s2 []int
+	s2 = append(xs, a)
+	var s3 []int
+	println(len(s3))
+	_ = s2
+}
+
+func deferClose1(filename string) error {
+	f, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	println(f.Name())
+	f.Close()
+	return nil
+}
+
+// Not checked: Close is not the last statement.
+
+func deferClose2(filename string) {
+	f, _ := os.Open(filename)
+	f.Close()
+	println(f.Name())
+}
+
+func lineReader(f *os.File) {
+	_ = bufio.NewReader(f)
+
+	// Not a line reading constructor:
+	_ = bufio.NewWriter(f)
+}
+
+func errScope(f func() error) error {
+	err := f()
+	if err != nil {
+		return err
+	}
+	err = f()
+	if err != nil {
+		return err
+	}
+
+	// Not an err check:
+	if x := f(); x == nil {
+		return nil
+	}
+	return nil
+}
+
+var ErrCompare = errors.New("compare")
+
+func errCompare(err error) bool {
+	_ = errors.Is(err, ErrCompare)
+	return errors.Is(err, ErrCompare)
+}
+
+func stringBuilder(prefix, name string) (string, []byte) {
+	var buf bytes.Buffer
+	buf.WriteString(prefix)
+	buf.WriteString(name)
+
+	// Not turned into a string:
+	data := bytes.Buffer{}
+	data.WriteByte(0)
+	return buf.String(), data.Bytes()
+}
+
+func multiAssign(x, y int) {
+	a := 1
+	b := "b"
+
+	// Multi-value calls:
+	f := func() (int, int) { return x, y }
+	x, y = f()
+	_, _ = a, b
+}
+
+func envLookup() string {
+	shell := os.Getenv("SHELL")
+	if shell == "" {
+		shell = "sh"
+	}
+	if os.Getenv("DEBUG") != "" {
+		println("debug")
+	}
+	return shell
+}
+
+const (
+	iotaA = iota
+	iotaB = iota
+	iotaC = iota
+)
+
+var stdImportGroup = []interface{}{path.Join, e2e1.ParseTestFile}
+
+type structVerbPoint struct{ X, Y int }
+
+func structVerb(p structVerbPoint) {
+	_ = fmt.Sprintf("point: %+v", p)
+	_ = fmt.Sprintf("point: %+v", &p)
+}
+
+const (
+	constBaseA = 0x10
+	constBaseB = 0x20
+	constBaseC = 0
+)
+
+func goroutineArgs(xs []int, done chan bool) {
+	go func() { _ = xs }()
+	go func() { done <- true }()
+	// Nothing is captured:
+	go func() { os.Exit(1) }()
+}
+
+func deferFunc(mu *sync.Mutex, cleanup func()) {
+	mu.Lock()
+	defer func() { mu.Unlock() }()
+	defer func() {
+		// Nested functions are inferred separately:
+		defer cleanup()
+	}()
+}
+
+func logger(std *log.Logger) {
+	log.Printf("started on %d", 80)
+	std.Fatal("failed")
+	// Not a logging call:
+	_ = slog.Int("port", 80)
+}
+
+type ctorPoint struct{ X, Y int }
+
+func NewPoint(x, y int) *ctorPoint { return &ctorPoint{X: x, Y: y} }
+
+func New() *ctorPoint { return &ctorPoint{} }
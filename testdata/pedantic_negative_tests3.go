@@ -0,0 +1,54 @@
+package pntests3
+
+// In this test suite void functions always end with explicit return
+// and slices are always initialized with make and append.
+// No warnings should be generated.
+// Only pedantic checks are tested here.
+
+func voidReturn1(x int) {
+	println(x)
+	return
+}
+
+func voidReturn2(ch chan int, x int) {
+	defer close(ch)
+	ch <- x
+	return
+}
+
+// Not checked: ends with panic, loop or conditional.
+
+func voidReturn3() {
+	panic("unreachable")
+}
+
+func voidReturn4(ch chan int) {
+	for {
+		println(<-ch)
+	}
+}
+
+func voidReturn5(x int) {
+	if x != 0 {
+		println(x)
+	}
+}
+
+func voidReturn6() {}
+
+func sliceInit(a int, xs []int) {
+	s1 := make([]int, 0, 1)
+	s1 = append(s1, a)
+	_ = s1
+
+	// Not an initialization with known elements:
+	s2 := make([]int, 0, len(xs))
+	_ = append(s2, a)
+	return
+}
+
+func sizedSliceMake(n int) {
+	_ = make([]int, 0, n)
+	_ = make([]string, 0, 10)
+	return
+}
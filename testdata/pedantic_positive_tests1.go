@@ -0,0 +1,628 @@
+package ptests1
+
+// In this test suite, (1) option is always preferred.
+// Only pedantic checks are tested here.
+
+import "bufio"
+
+import "context"
+
+import "errors"
+
+import "fmt"
+
+import "log"
+
+import "log/slog"
+
+import "os"
+
+import "reflect"
+
+import "sync"
+
+// The suggestion is fixed.
+//= dot import: avoid dot import, qualify the package identifiers (advisory, needs manual changes)
+import . "math"
+
+import (
+	"strings"
+
+	_ "errors"
+)
+
+import (
+	"bytes"
+
+	_ "os"
+)
+
+import (
+	"sort"
+	//= blank import: put blank imports into their own group
+	_ "io"
+)
+
+import (
+	"path"
+
+	e2e1 "github.com/Quasilyte/go-consistent/internal/end2end"
+)
+
+import (
+	"io/ioutil"
+
+	"github.com/go-toolsmith/astequal"
+)
+
+//= std import group: separate standard library imports from other imports with an empty line
+import (
+	"unicode"
+	"github.com/go-toolsmith/astcast"
+)
+
+func sliceConcat(a, b []int) {
+	a = append(a, b...)
+	a = append(a, a...)
+	//= slice concat: use `dst = append(dst, src...)`
+	copy(a[len(b):], b)
+}
+
+func mapIndex(m map[string]int, xs []int) {
+	v1, ok := m["a"]
+	var v2, ok2 = m["b"]
+	//= map index: use comma-ok form, like in `v, ok := m[k]`
+	v3 := m["c"]
+
+	// Not a map index.
+	v4 := xs[0]
+	_, _, _, _, _, _ = v1, v2, v3, v4, ok, ok2
+}
+
+type myError struct{}
+
+func (*myError) Error() string { return "" }
+
+func typedNilReturn(cond bool) error {
+	if cond {
+		return nil
+	}
+	var err *myError
+	if !cond {
+		//= typed nil return: return untyped nil instead of a nil pointer variable
+		return err
+	}
+	var err2 *myError = nil
+	//= typed nil return: return untyped nil instead of a nil pointer variable
+	return err2
+}
+
+func ifInit(f func() (int, error)) {
+	if v, err := f(); err != nil {
+		_ = v
+	}
+	if _, err := f(); err != nil {
+	}
+	//= if init: move the declaration into if init, like in `if x := f(); x != nil {}`
+	v, err := f()
+	if err != nil {
+		_ = v
+	}
+}
+
+func sliceClone(src []int) {
+	dst1 := append([]int(nil), src...)
+	dst2 := append([]int{}, src...)
+	//= slice clone: use `dst := append([]T(nil), src...)`
+	dst3 := make([]int, len(src))
+	copy(dst3, src)
+	_, _ = dst1, dst2
+}
+
+func rangeValue(xs []int, arr [4]string) {
+	for i := range xs {
+		println(xs[i])
+	}
+	for i := range arr {
+		_ = arr[i] + "!"
+	}
+	//= range value: use index-only loop, like in `for i := range s { use(s[i]) }`
+	for i, x := range xs {
+		println(i, x)
+	}
+}
+
+//= chan dir: annotate channel direction, like in `ch <-chan T`
+func chanDrain(ch chan int, done <-chan struct{}) {
+	for range ch {
+	}
+	for _ = range done {
+	}
+	//= chan drain: use range loop, like in `for range ch {}`
+	for {
+		if _, ok := <-ch; !ok {
+			break
+		}
+	}
+}
+
+func printlnCalls() {
+	fmt.Println("hello")
+	fmt.Println(`world`)
+	//= println: use Println, like in `fmt.Println("x")`
+	fmt.Printf("hello\n")
+}
+
+func voidReturn(x int) {
+	println(x)
+	//= void return: omit trailing `return` in void functions
+	return
+}
+
+func blankImport() {
+	_ = strings.ToUpper
+	_ = bytes.ToUpper
+	_ = sort.Ints
+}
+
+type chainBuilder struct{}
+
+func (b *chainBuilder) A() *chainBuilder { return b }
+func (b *chainBuilder) B() *chainBuilder { return b }
+func (b *chainBuilder) C() *chainBuilder { return b }
+
+func methodChain(x *chainBuilder) {
+	_ = x.A().B().C()
+	x.C().B().A().C()
+	//= method chain: chain method calls, like in `x.A().B().C()`
+	a := x.A()
+	b := a.B()
+	c := b.C()
+	_ = c
+}
+
+func fieldsSplit(s string) {
+	_ = strings.Fields(s)
+	_ = strings.Fields(s + s)
+	//= fields split: use strings.Fields(s) for whitespace splitting
+	_ = strings.Split(s, " ")
+}
+
+func sliceDelete(xs, ys []int, i int) {
+	xs = append(xs[:i], xs[i+1:]...)
+	ys = append(ys[:i], ys[i+1:]...)
+	//= slice delete: use `s = append(s[:i], s[i+1:]...)` to delete element
+	for j := i; j < len(xs)-1; j++ {
+		xs[j] = xs[j+1]
+	}
+	xs = xs[:len(xs)-1]
+}
+
+func mapInit() {
+	m1 := map[string]int{"a": 1}
+	m2 := map[int]bool{1: true, 2: false}
+	//= map init: use map literal, like in `m := map[K]V{k: v}`
+	m3 := make(map[string]int)
+	m3["a"] = 1
+	_, _, _ = m1, m2, m3
+}
+
+func sliceInit(a, b int) {
+	s1 := []int{a, b}
+	s2 := []string{"x"}
+	//= slice init: use slice literal, like in `s := []T{a, b}`
+	var s3 []int
+	s3 = append(s3, a, b)
+	_, _, _ = s1, s2, s3
+}
+
+func deferClose1(filename string) error {
+	f, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	println(f.Name())
+	return nil
+}
+
+func deferClose2(filename string) {
+	f, _ := os.Create(filename)
+	defer f.Close()
+	println(f.Name())
+}
+
+func deferClose3(filename string) error {
+	//= defer close: defer Close right after the file is opened
+	f, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	println(f.Name())
+	f.Close()
+	return nil
+}
+
+func lineReader(f *os.File) {
+	_ = bufio.NewScanner(f)
+	_ = bufio.NewScanner(os.Stdin)
+	//= line reader: use bufio.NewScanner for reading lines
+	_ = bufio.NewReader(f)
+}
+
+func errScope(f func() error) error {
+	if err := f(); err != nil {
+		return err
+	}
+	if err := f(); err != nil {
+		return err
+	}
+	var err error
+	//= err scope: scope err to the if statement, like in `if err := f(); err != nil {}`
+	err = f()
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+var ErrCompare error = errors.New("compare")
+
+var errCompare error = errors.New("compare")
+
+func errCompare1(err error) bool {
+	// errors.Is is suggested since both forms are used.
+	//= err compare: compare errors with `errors.Is(err, ErrFoo)`
+	_ = err == ErrCompare
+	//= err compare: compare errors with `errors.Is(err, ErrFoo)`
+	_ = err != errCompare
+	return errors.Is(err, ErrCompare)
+}
+
+func nilCheck(p *int, m map[int]int, iface interface{}) {
+	// The suggestion is fixed, regardless of the usage counts.
+	//= nil check: use `x == nil` instead of `reflect.ValueOf(x).IsNil()`
+	_ = reflect.ValueOf(p).IsNil()
+	//= nil check: use `x == nil` instead of `reflect.ValueOf(x).IsNil()`
+	_ = reflect.ValueOf(m).IsNil()
+	_ = p == nil
+
+	// Interfaces can hold typed nil values:
+	_ = reflect.ValueOf(iface).IsNil()
+}
+
+func stringBuilder(prefix, name string) (string, string) {
+	var sb strings.Builder
+	sb.WriteString(prefix)
+	sb.WriteString(name)
+	// strings.Builder is suggested since both types are used.
+	//= string builder: use strings.Builder to build strings
+	buf := bytes.Buffer{}
+	buf.WriteString(prefix)
+	buf.WriteString(name)
+	return sb.String(), buf.String()
+}
+
+type stringerValue int
+
+func (stringerValue) String() string { return "" }
+
+func fmtStringer(x stringerValue, w *os.File) {
+	_ = fmt.Sprintf("%s", x)
+	// The suggestion is fixed, regardless of the usage counts.
+	//= fmt stringer: remove explicit String call, fmt calls it for %s and %v
+	_ = fmt.Sprintf("%d: %v", 1, x.String())
+	//= fmt stringer: remove explicit String call, fmt calls it for %s and %v
+	fmt.Fprintf(w, "%s\n", x.String())
+	//= fmt stringer: remove explicit String call, fmt calls it for %s and %v
+	_ = fmt.Errorf("%-10s", x.String())
+
+	// Not a %s or %v verb:
+	_ = fmt.Sprintf("%q", x.String())
+	// Explicit argument indexes:
+	_ = fmt.Sprintf("%[1]s", x.String())
+}
+
+func multiAssign(x int) {
+	a, b := 1, "b"
+	x, a = 0, -1
+	//= multi assign: group independent assignments, like in `a, b := 1, 2`
+	c := x
+	d := true
+	_, _, _, _ = a, b, c, d
+}
+
+func envLookup() (string, string) {
+	home, ok := os.LookupEnv("HOME")
+	if !ok {
+		home = "/"
+	}
+	// os.LookupEnv is suggested since both forms are used.
+	//= env lookup: use os.LookupEnv to check whether the variable is set
+	shell := os.Getenv("SHELL")
+	if shell == "" {
+		shell = "sh"
+	}
+	//= env lookup: use os.LookupEnv to check whether the variable is set
+	if os.Getenv("DEBUG") != "" {
+		println("debug")
+	}
+	return home, shell
+}
+
+type doubleNegationConfig struct {
+	NoCache bool
+}
+
+func doubleNegation(conf doubleNegationConfig, disabled, notFound, nothing bool) {
+	if disabled {
+		return
+	}
+	// The suggestion is fixed, regardless of the usage counts.
+	//= double negation: avoid double negation, invert the condition or rename the variable
+	if !conf.NoCache {
+		println("cache")
+	}
+	//= double negation: avoid double negation, invert the condition or rename the variable
+	for nothing && !notFound {
+	}
+
+	// Not a negative prefix word:
+	if !nothing {
+	}
+}
+
+type errResult struct{ n int }
+
+func errReturn(f func() (int, error)) (errResult, int, error) {
+	var zero errResult
+	n, err := f()
+	if err != nil {
+		return errResult{}, 0, err
+	}
+	n, err = f()
+	if err != nil {
+		// Not a zero value:
+		return errResult{}, n, err
+	}
+	n, err = f()
+	if err != nil {
+		//= err return: return zero value literals in error paths, like in `return 0, err`
+		return zero, 0, err
+	}
+	return zero, n, nil
+}
+
+func chanDir(in <-chan int, out chan<- int, errs chan error) {
+	for x := range in {
+		out <- x
+	}
+	close(out)
+
+	// Passed to another function:
+	//= goroutine args: pass variables to goroutines as arguments, like in `go func(x T) {...}(x)`
+	go func() { _ = errs }()
+}
+
+func redundantParens(x int, s []int) int {
+	// The suggestion is fixed.
+	//= redundant parens: remove redundant parenthesis, like in `return x`
+	_ = s[(x)]
+	//= redundant parens: remove redundant parenthesis, like in `return x`
+	_ = (int)(x)
+
+	// Not a single operand:
+	_ = (x + 1) * 2
+	//= redundant parens: remove redundant parenthesis, like in `return x`
+	return (0)
+}
+
+const (
+	iotaA = iota
+	iotaB
+	iotaC
+	//= iota repeat: omit repeated iota expression, like in `const (A Kind = iota; B)`
+	iotaD = iota
+)
+
+type iotaKind int
+
+const (
+	kindA iotaKind = iota
+	kindB
+	//= iota repeat: omit repeated iota expression, like in `const (A Kind = iota; B)`
+	kindC iotaKind = iota
+)
+
+type structTags struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	//= struct tags: add a field tag, like the most of the struct fields have
+	Email string
+	Skip  bool `json:"-"`
+
+	// Not serialized:
+	structTagsBase
+	cache map[string]int
+}
+
+type structTagsBase struct{}
+
+var dotImport = Pi
+
+var stdImportGroup = []interface{}{path.Join, ioutil.ReadAll, unicode.IsUpper, e2e1.ParseTestFile, astequal.Expr, astcast.ToIdent}
+
+type structVerbPoint struct{ X, Y int }
+
+func structVerb(p structVerbPoint, pp *structVerbPoint, s stringerValue, n int) {
+	_ = fmt.Sprintf("point: %v", p)
+	_ = fmt.Sprintf("point: %v", pp)
+	//= struct verb: format structs with %v, like other format calls do
+	_ = fmt.Sprintf("point: %+v", p)
+	//= struct verb: format structs with %v, like other format calls do
+	_ = fmt.Sprintf("point: %d %#v", n, pp)
+
+	// Not a struct:
+	_ = fmt.Sprintf("%+v %#v", n, s)
+}
+
+type zeroFieldOptions struct {
+	Name    string
+	Count   int
+	Ratio   float64
+	Enabled bool
+	Next    *zeroFieldOptions
+	Value   interface{}
+}
+
+func zeroField(name string) []*zeroFieldOptions {
+	return []*zeroFieldOptions{
+		{Name: name},
+		{
+			Name: name,
+			// The suggestion is fixed.
+			//= zero field: omit zero value fields, like in `T{Name: name}`
+			Count: 0,
+			//= zero field: omit zero value fields, like in `T{Name: name}`
+			Ratio: 0.0,
+			//= zero field: omit zero value fields, like in `T{Name: name}`
+			Enabled: false,
+			//= zero field: omit zero value fields, like in `T{Name: name}`
+			Next: nil,
+		},
+		{
+			//= zero field: omit zero value fields, like in `T{Name: name}`
+			Name: "",
+			// Not a zero value of the field type:
+			Value: 0,
+			Count: 1,
+			Ratio: 0.5,
+		},
+	}
+}
+
+const (
+	constBaseA = 10
+	constBaseB = 20
+	//= const base: use decimal literals, like other constants of this block
+	constBaseC = 0x1e
+)
+
+// Inferred separately from other const blocks.
+const (
+	constFlagNone = 0
+	constFlagA    = 0x1
+	constFlagB    = 0x2
+	//= const base: use hex literals, like other constants of this block
+	constFlagC = 4
+	//= const base: use hex literals, like other constants of this block
+	constFlagD = 0o10
+)
+
+func ctxParamFirst(ctx context.Context, x int) {}
+
+// The suggestion is fixed.
+//= ctx param: make context.Context the first parameter, like in `f(ctx context.Context, x T)`
+func ctxParamLast(x, y int, ctx context.Context) {}
+
+func sliceBounds(s []int, str string, arr [4]int, i int) {
+	_ = s[:]
+	_ = s[i:]
+	_ = str[:i]
+
+	// The suggestion is fixed.
+	//= slice bounds: omit default slice bounds, like in `s[:]` and `s[i:]`
+	_ = s[0:]
+	//= slice bounds: omit default slice bounds, like in `s[:]` and `s[i:]`
+	_ = s[i:len(s)]
+	//= slice bounds: omit default slice bounds, like in `s[:]` and `s[i:]`
+	_ = str[0:len(str)]
+	//= slice bounds: omit default slice bounds, like in `s[:]` and `s[i:]`
+	_ = arr[0x0:i]
+	//= slice bounds: omit default slice bounds, like in `s[:]` and `s[i:]`
+	_ = s[0:i:len(s)]
+}
+
+func goroutineArgs(xs []int, done chan<- bool) {
+	go func(xs []int) { _ = xs }(xs)
+	go func(done chan<- bool) { done <- true }(done)
+	go func(x int) { _ = x }(len(xs))
+	// Nothing is captured:
+	go func() { os.Exit(1) }()
+
+	for i := range xs {
+		//= goroutine args: pass variables to goroutines as arguments, like in `go func(x T) {...}(x)`
+		go func() { _ = xs[i] }()
+	}
+}
+
+func deferFunc(mu *sync.Mutex, cleanup func()) {
+	mu.Lock()
+	defer mu.Unlock()
+	defer cleanup()
+	//= defer func: defer named function calls, like in `defer cleanup()`
+	defer func() { cleanup() }()
+}
+
+// Inferred separately from other functions.
+func deferFuncLit(cleanup func()) {
+	defer func() { cleanup() }()
+	defer func() { recover() }()
+	//= defer func: defer function literals, like in `defer func() {...}()`
+	defer cleanup()
+}
+
+func logger(l *slog.Logger, std *log.Logger) {
+	slog.Info("started", "port", 80)
+	l.Error("failed", "err", errors.New("timeout"))
+	l.DebugContext(context.Background(), "retry")
+	//= logger: use the structured logger instead of the log package
+	log.Printf("started on %d", 80)
+	//= logger: use the structured logger instead of the log package
+	std.Println("done")
+
+	// Not a logging call:
+	log.SetFlags(0)
+	_ = slog.String("port", "80")
+}
+
+func slogMsg(ctx context.Context, l *slog.Logger, port int) {
+	slog.Info("started", "port", port)
+	l.Warn(fmt.Sprint("slow"))
+
+	// The suggestion is fixed.
+	//= slog msg: pass values as key-value attributes, like in `slog.Info("msg", "key", v)`
+	slog.Info(fmt.Sprintf("started on %d", port))
+	//= slog msg: pass values as key-value attributes, like in `slog.Info("msg", "key", v)`
+	l.ErrorContext(ctx, fmt.Sprintf("failed on %d", port))
+	//= slog msg: pass values as key-value attributes, like in `slog.Info("msg", "key", v)`
+	l.Log(ctx, slog.LevelInfo, fmt.Sprintf("started on %d", port))
+}
+
+type ctorPoint struct{ X, Y int }
+
+type ctorRange struct{ Low, High int }
+
+type ctorSize struct{ W, H int }
+
+type ctorBuffer struct{ data [64]byte }
+
+func NewPoint(x, y int) ctorPoint { return ctorPoint{X: x, Y: y} }
+
+func NewRange(low, high int) (ctorRange, error) { return ctorRange{Low: low, High: high}, nil }
+
+//= ctor result: return small structs by value from constructors, like other constructors do
+func NewSize(w, h int) *ctorSize { return &ctorSize{W: w, H: h} }
+
+// Not a small struct:
+func NewBuffer() *ctorBuffer { return &ctorBuffer{} }
+
+// Not a constructor name:
+func Newest() *ctorSize { return &ctorSize{} }
+
+func sizedSliceMake(n int) {
+	_ = make([]int, n)
+	_ = make([]string, 10)
+	//= sized slice make: use make([]T, n)
+	_ = make([]int, 0, n)
+}
@@ -0,0 +1,320 @@
+package ptests2
+
+// In this test suite, (2) option is always preferred.
+// Only pedantic checks are tested here.
+
+import "bufio"
+
+import "fmt"
+
+import "log"
+
+import "log/slog"
+
+import "os"
+
+import "sync"
+
+import (
+	"strings"
+	_ "errors"
+)
+
+import (
+	_ "os"
+	"bytes"
+)
+
+import (
+	"sort"
+
+	//= blank import: put blank imports in line with other imports
+	_ "io"
+)
+
+import (
+	"path"
+	e2e1 "github.com/Quasilyte/go-consistent/internal/end2end"
+)
+
+import (
+	"io/ioutil"
+	"github.com/go-toolsmith/astequal"
+)
+
+//= std import group: don't separate standard library imports from other imports
+import (
+	"unicode"
+
+	"github.com/go-toolsmith/astcast"
+)
+
+func sliceConcat(a, b []int) {
+	//= slice concat: use `copy(dst[len(x):], src)`
+	a = append(a, b...)
+	copy(a[len(b):], b)
+	copy(b[len(a):], a)
+}
+
+func mapIndex(m map[string]int) {
+	v1 := m["a"]
+	var v2 = m["b"]
+	//= map index: use single-value form, like in `v := m[k]`
+	v3, ok := m["c"]
+	_, _, _, _ = v1, v2, v3, ok
+}
+
+func ifInit(f func() (int, error)) {
+	v, err := f()
+	if err != nil {
+		_ = v
+	}
+	x, _ := f()
+	if x == 0 {
+		return
+	}
+	//= if init: move the declaration out of if init, like in `x := f(); if x != nil {}`
+	if _, err := f(); err != nil {
+	}
+}
+
+func sliceClone(src []int) {
+	dst1 := make([]int, len(src))
+	copy(dst1, src)
+	var dst2 []int
+	dst2 = make([]int, len(src))
+	copy(dst2, src)
+	//= slice clone: use `dst := make([]T, len(src)); copy(dst, src)`
+	dst3 := append([]int(nil), src...)
+	_ = dst3
+}
+
+func rangeValue(xs []int, arr [4]string) {
+	for i, x := range xs {
+		println(i, x)
+	}
+	for _, s := range arr {
+		println(s)
+	}
+	//= range value: use value loop, like in `for i, v := range s { use(v) }`
+	for i := range xs {
+		println(xs[i])
+	}
+}
+
+//= chan dir: use bidirectional channel type, like in `ch chan T`
+func chanDrain(ch chan int, done <-chan struct{}) {
+	for {
+		<-ch
+	}
+	for {
+		<-done
+	}
+	//= chan drain: use receive loop, like in `for { if _, ok := <-ch; !ok { break } }`
+	for range ch {
+	}
+}
+
+func printlnCalls() {
+	fmt.Printf("hello\n")
+	fmt.Printf(`world
+`)
+	//= println: use Printf, like in `fmt.Printf("x\n")`
+	fmt.Println("hello")
+}
+
+func blankImport() {
+	_ = strings.ToUpper
+	_ = bytes.ToUpper
+	_ = sort.Ints
+}
+
+type chainBuilder struct{}
+
+func (b *chainBuilder) A() *chainBuilder { return b }
+func (b *chainBuilder) B() *chainBuilder { return b }
+func (b *chainBuilder) C() *chainBuilder { return b }
+
+func methodChain(x *chainBuilder) {
+	a := x.A()
+	b := a.B()
+	c := b.C()
+	_ = c
+	y := x.C()
+	y = y.B()
+	y = y.A()
+	//= method chain: assign method call results to variables, like in `a := x.A(); b := a.B()`
+	_ = x.A().B().C()
+}
+
+func fieldsSplit(s string) {
+	_ = strings.Split(s, " ")
+	_ = strings.Split(s, "\t")
+	//= fields split: use strings.Split(s, " ") for whitespace splitting
+	_ = strings.Fields(s)
+}
+
+func sliceDelete(xs, ys []int, i int) {
+	for j := i; j < len(xs)-1; j++ {
+		xs[j] = xs[j+1]
+	}
+	xs = xs[:len(xs)-1]
+	for j := i; j < len(ys)-1; j++ {
+		ys[j] = ys[j+1]
+	}
+	ys = ys[:len(ys)-1]
+	//= slice delete: use shifting loop followed by `s = s[:len(s)-1]` to delete element
+	xs = append(xs[:i], xs[i+1:]...)
+}
+
+func mapInit() {
+	m1 := make(map[string]int)
+	m1["a"] = 1
+	m2 := make(map[int]bool, 2)
+	m2[1] = true
+	m2[2] = false
+	//= map init: use make and assignments, like in `m := make(map[K]V); m[k] = v`
+	m3 := map[string]int{"a": 1}
+	_, _, _ = m1, m2, m3
+}
+
+func sliceInit(a, b int) {
+	var s1 []int
+	s1 = append(s1, a, b)
+	var s2 []string
+	s2 = append(s2, "x")
+	//= slice init: use append, like in `s := make([]T, 0, 2); s = append(s, a, b)`
+	s3 := []int{a, b}
+	_, _, _ = s1, s2, s3
+}
+
+func deferClose1(filename string) error {
+	f, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	println(f.Name())
+	f.Close()
+	return nil
+}
+
+func deferClose2(filename string) {
+	f, _ := os.Create(filename)
+	println(f.Name())
+	f.Close()
+}
+
+func deferClose3(filename string) error {
+	//= defer close: call Close at the end of the block instead of defer
+	f, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	println(f.Name())
+	return nil
+}
+
+func lineReader(f *os.File) {
+	_ = bufio.NewReader(f)
+	_ = bufio.NewReader(os.Stdin)
+	//= line reader: use bufio.NewReader for reading lines
+	_ = bufio.NewScanner(f)
+}
+
+func multiAssign(x int) {
+	a := 1
+	b := "b"
+	x = 0
+	a = -1
+	//= multi assign: assign one variable per statement, like in `a := 1; b := 2`
+	c, d := x, true
+	_, _, _, _ = a, b, c, d
+}
+
+func errReturn(f func() (int, error)) (res string, n int, err error) {
+	var zero int
+	_, err = f()
+	if err != nil {
+		return res, zero, err
+	}
+	_, err = f()
+	if err != nil {
+		return res, zero, err
+	}
+	_, err = f()
+	if err != nil {
+		//= err return: return zero value variables in error paths, like in `return zero, err`
+		return "", zero, err
+	}
+	return "ok", 1, nil
+}
+
+func chanDir(in chan int, out chan int) {
+	for x := range in {
+		out <- x
+	}
+	close(out)
+}
+
+const (
+	iotaA = 1 << iota
+	iotaB = 1 << iota
+	iotaC = 1 << iota
+	//= iota repeat: repeat iota expression, like in `const (A Kind = iota; B Kind = iota)`
+	iotaD
+)
+
+var stdImportGroup = []interface{}{path.Join, ioutil.ReadAll, unicode.IsUpper, e2e1.ParseTestFile, astequal.Expr, astcast.ToIdent}
+
+type structVerbPoint struct{ X, Y int }
+
+func structVerb(p structVerbPoint, pp *structVerbPoint) error {
+	_ = fmt.Sprintf("point: %+v", p)
+	_ = fmt.Sprintf("point: %+v", pp)
+	//= struct verb: format structs with %+v, like other format calls do
+	return fmt.Errorf("point: %v", p)
+}
+
+const (
+	constBaseA = 0x10
+	constBaseB = 0x20
+	//= const base: use hex literals, like other constants of this block
+	constBaseC = 48
+)
+
+func goroutineArgs(xs []int, done chan bool) {
+	go func() { _ = xs }()
+	go func() { done <- true }()
+
+	//= goroutine args: capture variables in goroutines, like in `go func() {...}()`
+	go func(n int) { _ = n }(len(xs))
+}
+
+func deferFunc(mu *sync.Mutex, cleanup func()) {
+	mu.Lock()
+	defer func() { mu.Unlock() }()
+	defer func() { cleanup() }()
+	//= defer func: defer function literals, like in `defer func() {...}()`
+	defer cleanup()
+}
+
+func logger(l *slog.Logger) {
+	log.Printf("started on %d", 80)
+	log.Print("done")
+	//= logger: use the log package instead of the structured logger
+	l.Info("started", "port", 80)
+}
+
+type ctorPoint struct{ X, Y int }
+
+type ctorRange struct{ Low, High int }
+
+type ctorSize struct{ W, H int }
+
+func NewPoint(x, y int) *ctorPoint { return &ctorPoint{X: x, Y: y} }
+
+func NewRange(low, high int) *ctorRange { return &ctorRange{Low: low, High: high} }
+
+//= ctor result: return small structs by pointer from constructors, like other constructors do
+func NewSize(w, h int) ctorSize { return ctorSize{W: w, H: h} }
@@ -0,0 +1,59 @@
+package ptests3
+
+// In this test suite void functions prefer explicit trailing return,
+// slices are initialized with make and append and err variables are reused.
+// Only pedantic checks are tested here.
+
+func voidReturn1(x int) {
+	println(x)
+	return
+}
+
+func voidReturn2(x int) {
+	x++
+	return
+}
+
+func voidReturn3(x int) {
+	//= void return: end void functions with explicit `return`
+	println(x)
+}
+
+func sliceInit(a, b int) {
+	s1 := make([]int, 0, 2)
+	s1 = append(s1, a, b)
+	s2 := make([]string, 0, 1)
+	s2 = append(s2, "x")
+	//= slice init: use append, like in `s := make([]T, 0, 2); s = append(s, a, b)`
+	s3 := []int{a, b}
+	_, _, _ = s1, s2, s3
+	return
+}
+
+func errScope(f func() error) error {
+	err := f()
+	if err != nil {
+		return err
+	}
+	err = f()
+	if err != nil {
+		return err
+	}
+	err = f()
+	if err != nil {
+		return err
+	}
+	//= err scope: reuse err variable, like in `err = f(); if err != nil {}`
+	if err := f(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func sizedSliceMake(n int) {
+	//= sized slice make: use make([]T, 0, n)
+	_ = make([]int, n)
+	_ = make([]string, 0, 10)
+	_ = make([]int, 0, n)
+	return
+}
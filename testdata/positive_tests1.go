@@ -6,6 +6,8 @@ import "strconv"
 
 import "errors"
 
+import "time"
+
 //= unit import: omit parenthesis in a single-package import
 import (
 	"fmt"
@@ -15,6 +17,7 @@ var (
 	_ = fmt.Printf
 	_ = errors.New
 	_ = strconv.Atoi
+	_ = time.Now
 )
 
 // T is an example type.
@@ -169,3 +172,71 @@ func allTypes2(a int, b int, c int) {
 func omitTypes(a, b, c int) { //= use types always after each argument
 	return
 }
+
+func typeAssert(x interface{}) {
+	v1, ok := x.(int)
+	var v2, ok2 = x.(string)
+	//= type assert: use comma-ok form, like in `v, ok := x.(T)`
+	v3 := x.(float64)
+	_, _, _, _, _ = v1, v2, v3, ok, ok2
+}
+
+func scalarPtrAlloc() {
+	_ = new(int)
+	_ = new(string)
+	//= scalar ptr alloc: use new(T) for scalar *T allocation
+	x := 0
+	p := &x
+	_ = p
+
+	// Non-zero values can't be allocated with new(T).
+	y := 10
+	py := &y
+	_ = py
+}
+
+func durationLit(n int) {
+	_ = 5 * time.Second
+	_ = time.Minute * 10
+	//= duration lit: use untyped constant, like in `5 * time.Second`
+	_ = time.Duration(100) * time.Millisecond
+
+	// Non-constant operands always require a conversion.
+	_ = time.Duration(n) * time.Second
+}
+
+func convSpelling(s string) {
+	_ = []byte(s)
+	_ = byte('a')
+	//= byte conv: use byte in conversions
+	_ = []uint8(s)
+
+	_ = []rune(s)
+	_ = rune('b')
+	//= rune conv: use rune in conversions
+	_ = int32('c')
+}
+
+type receiverKind struct{ x int }
+
+func (r *receiverKind) A() int { return r.x }
+func (r *receiverKind) B() int { return r.x }
+
+//= receiver kind: use pointer receiver, like other methods of this type
+func (r receiverKind) C() int { return r.x }
+
+// Inferred separately from receiverKind methods.
+type otherReceiverKind struct{}
+
+func (otherReceiverKind) A() {}
+
+func errorString(err error) error {
+	_ = errors.New("not found")
+	// The suggestion is fixed.
+	//= error string: use lower case error strings without trailing punctuation, like in `errors.New("not found")`
+	_ = errors.New("Not found")
+	//= error string: use lower case error strings without trailing punctuation, like in `errors.New("not found")`
+	_ = errors.New("not found.")
+	//= error string: use lower case error strings without trailing punctuation, like in `errors.New("not found")`
+	return fmt.Errorf("Load config: %v!", err)
+}
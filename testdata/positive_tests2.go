@@ -13,10 +13,15 @@ import (
 	"fmt"
 )
 
+import (
+	"time"
+)
+
 var (
 	_ = fmt.Printf
 	_ = errors.New
 	_ = strconv.Atoi
+	_ = time.Now
 )
 
 // T is an example type.
@@ -172,3 +177,56 @@ func omitTypes2(a, b, c int) {
 func allTypes(a int, b int, c int) { //= use only one type declaration after several arguments of the same type
 	return
 }
+
+func typeAssert(x interface{}) {
+	v1 := x.(int)
+	var v2 = x.(string)
+	//= type assert: use single-value form, like in `v := x.(T)`
+	v3, ok := x.(float64)
+	_, _, _, _ = v1, v2, v3, ok
+}
+
+func scalarPtrAlloc() {
+	//= scalar ptr alloc: use `x := v; p := &x` for scalar *T allocation
+	_ = new(int)
+	x := 0
+	p := &x
+	s := ""
+	var ps *string
+	ps = &s
+	b := false
+	pb := &b
+	_, _, _ = p, ps, pb
+}
+
+func durationLit() {
+	//= duration lit: use conversion, like in `time.Duration(5) * time.Second`
+	_ = 5 * time.Second
+	_ = time.Minute * time.Duration(10)
+	_ = time.Duration(100) * time.Millisecond
+}
+
+func convSpelling(s string) {
+	_ = []uint8(s)
+	_ = uint8('a')
+	//= byte conv: use uint8 in conversions
+	_ = []byte(s)
+
+	_ = []int32(s)
+	_ = int32('b')
+	//= rune conv: use int32 in conversions
+	_ = []rune(s)
+}
+
+type receiverKind struct{ x int }
+
+func (r receiverKind) A() int { return r.x }
+func (r receiverKind) B() int { return r.x }
+
+//= receiver kind: use value receiver, like other methods of this type
+func (r *receiverKind) C() int { return r.x }
+
+// Inferred separately from receiverKind methods.
+type otherReceiverKind struct{}
+
+func (*otherReceiverKind) A() {}
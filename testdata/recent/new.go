@@ -0,0 +1,6 @@
+package recent
+
+func newStyle() {
+	_ = map[int]int{}
+	_ = map[int]int{}
+}
@@ -0,0 +1,7 @@
+package recent
+
+func oldStyle() {
+	_ = make(map[int]int)
+	_ = make(map[int]int)
+	_ = make(map[int]int)
+}
@@ -0,0 +1,19 @@
+package suppress
+
+func maps() {
+	_ = map[int]int{}
+	_ = map[int]int{}
+	_ = map[int]int{}
+	_ = map[int]int{}
+
+	_ = make(map[int]int) //go-consistent:ignore empty map
+
+	//go-consistent:ignore
+	_ = make(map[int]int)
+
+	//go-consistent:ignore hex lit
+	_ = make(map[int]int)
+
+	//go-consistent:ignore
+	_ = 0
+}
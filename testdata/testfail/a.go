@@ -0,0 +1,10 @@
+package testfail
+
+import "testing"
+
+func notTest(t *testing.T, x int) {
+	// Not a test file:
+	if x != 0 {
+		t.Fatal(x)
+	}
+}
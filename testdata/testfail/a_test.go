@@ -0,0 +1,38 @@
+package testfail
+
+import "testing"
+
+func TestA(t *testing.T) {
+	got, want := 1, 2
+	if got != want {
+		t.Errorf("got %d, want %d", got, want)
+	}
+	if got == 0 {
+		t.Error("zero")
+	}
+	if got < 0 {
+		//= test fail: report failed checks with t.Error, like in `t.Errorf(format, got, want)`
+		t.Fatalf("negative: %d", got)
+	}
+
+	// Not a failed check reporting:
+	t.Fatal("unconditional")
+	if got > want {
+		t.Log("greater")
+		t.Fatal("greater")
+	}
+}
+
+func BenchmarkA(b *testing.B) {
+	// Not a *testing.T:
+	if b.N < 0 {
+		b.Fatal("negative")
+	}
+}
+
+func helper(t testing.TB) {
+	// Not a *testing.T:
+	if t == nil {
+		t.Fatal("nil")
+	}
+}
@@ -0,0 +1,47 @@
+package testhelper
+
+import "testing"
+
+func TestA(t *testing.T) {
+	checkA(t, 1)
+	checkB(t, 1)
+	checkC(t, 1)
+}
+
+func checkA(t *testing.T, x int) {
+	t.Helper()
+	if x != 1 {
+		t.Fatalf("x = %d", x)
+	}
+}
+
+func checkB(t *testing.T, x int) {
+	t.Helper()
+	if x != 1 {
+		t.Fatalf("x = %d", x)
+	}
+}
+
+//= test helper: call t.Helper() in test helpers, like other helpers do
+func checkC(tt *testing.T, x int) {
+	if x != 1 {
+		tt.Fatalf("x = %d", x)
+	}
+}
+
+func benchHelper(b *testing.B) {
+	b.Helper()
+	b.Fatal("unexpected")
+}
+
+// Not a test helper, doesn't report failures:
+func logValue(t *testing.T, x int) {
+	t.Logf("x = %d", x)
+}
+
+// Not a *testing.T first parameter:
+func checkD(x int, t *testing.T) {
+	if x != 1 {
+		t.Fatalf("x = %d", x)
+	}
+}
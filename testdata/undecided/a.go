@@ -0,0 +1,6 @@
+package undecided
+
+func emptyMaps() {
+	_ = map[int]int{}
+	_ = make(map[int]int)
+}
@@ -0,0 +1,22 @@
+package wtests
+
+// In this test suite, warnings are annotated with analysistest-style
+// `// want "regexp"` comments on the reported lines.
+
+func hexLit() {
+	_ = 0xff
+	_ = 0xabcdef
+	_ = 0xABC // want `hex lit: use a-f \(lower case\) digits`
+	_ = 0xDEF // want "^hex lit: "
+
+	// The "//" of the string literal is not a comment.
+	_ = 0xbcd
+	_, _ = "http://x", 0xFED // want "^hex lit: "
+}
+
+func emptyMap() {
+	_ = make(map[int]int)
+	_ = make(map[string]int)
+	//= empty map: use make(map[K]V)
+	_ = map[int]int{}
+}
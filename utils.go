@@ -2,6 +2,13 @@ package main
 
 import (
 	"go/ast"
+	"go/token"
+	"go/types"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/go-toolsmith/astcast"
 )
 
 func valueOf(x ast.Node) string {
@@ -14,3 +21,223 @@ func valueOf(x ast.Node) string {
 		return ""
 	}
 }
+
+// normalizedValueOf returns a canonical representation of the numeric
+// literal x value, see normalizeLit.
+// For other nodes, it returns empty string.
+func normalizedValueOf(x ast.Node) string {
+	lit, ok := x.(*ast.BasicLit)
+	if !ok {
+		return ""
+	}
+	return normalizeLit(lit.Kind, valueOf(lit))
+}
+
+// normalizeLit returns a canonical representation of the numeric literal
+// value, so different spellings of the same value are normalized to the
+// same string, like "255" for 0xff, 0XFF and 0b1111_1111.
+// Returns empty string for malformed and non-numeric literals.
+func normalizeLit(kind token.Token, value string) string {
+	switch kind {
+	case token.INT:
+		// big.Int handles the prefixes and underscores like the Go syntax,
+		// untyped constants can overflow uint64.
+		x, ok := new(big.Int).SetString(value, 0)
+		if !ok {
+			return ""
+		}
+		return x.String()
+	case token.FLOAT:
+		x, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return ""
+		}
+		return strconv.FormatFloat(x, 'g', -1, 64)
+	default:
+		return ""
+	}
+}
+
+// sameLitValue reports whether the alt spelling denotes
+// the same value as the numeric literal lit.
+func sameLitValue(lit *ast.BasicLit, alt string) bool {
+	v := normalizedValueOf(lit)
+	return v != "" && v == normalizeLit(lit.Kind, alt)
+}
+
+// assignParts returns the number of LHS operands and the RHS expressions
+// of assignment-like nodes: *ast.AssignStmt and *ast.ValueSpec.
+// For other nodes, it returns 0 and nil.
+func assignParts(n ast.Node) (lhsLen int, rhs []ast.Expr) {
+	switch n := n.(type) {
+	case *ast.AssignStmt:
+		return len(n.Lhs), n.Rhs
+	case *ast.ValueSpec:
+		return len(n.Names), n.Values
+	default:
+		return 0, nil
+	}
+}
+
+// inspectFuncBody calls visit for every body node except
+// those that belong to the nested function literals.
+func inspectFuncBody(body *ast.BlockStmt, visit func(n ast.Node)) {
+	ast.Inspect(body, func(n ast.Node) bool {
+		if _, ok := n.(*ast.FuncLit); ok {
+			return false
+		}
+		if n != nil {
+			visit(n)
+		}
+		return true
+	})
+}
+
+// stmtList returns a statements list of the block-like nodes:
+// *ast.BlockStmt, *ast.CaseClause and *ast.CommClause.
+// For other nodes, it returns nil.
+func stmtList(n ast.Node) []ast.Stmt {
+	switch n := n.(type) {
+	case *ast.BlockStmt:
+		return n.List
+	case *ast.CaseClause:
+		return n.Body
+	case *ast.CommClause:
+		return n.Body
+	default:
+		return nil
+	}
+}
+
+// refersTo reports whether any identifier inside n refers to obj.
+func refersTo(info *types.Info, n ast.Node, obj types.Object) bool {
+	found := false
+	ast.Inspect(n, func(n ast.Node) bool {
+		if id, ok := n.(*ast.Ident); ok && info.ObjectOf(id) == obj {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// qualifiedIdent returns the package path and the name of the
+// package-level object that x refers to, like "time" and "Second"
+// for `time.Second`.
+// If x is not a qualified identifier, empty strings are returned.
+func qualifiedIdent(info *types.Info, x ast.Expr) (pkgPath, name string) {
+	sel, ok := x.(*ast.SelectorExpr)
+	if !ok {
+		return "", ""
+	}
+	id, ok := sel.X.(*ast.Ident)
+	if !ok {
+		return "", ""
+	}
+	pkgName, ok := info.Uses[id].(*types.PkgName)
+	if !ok {
+		return "", ""
+	}
+	return pkgName.Imported().Path(), sel.Sel.Name
+}
+
+// isBuiltin reports whether fn is an identifier that refers to
+// the name builtin function, like "len" for `len`.
+// Shadowed builtin names are not reported.
+func isBuiltin(info *types.Info, fn ast.Expr, name string) bool {
+	id, ok := fn.(*ast.Ident)
+	if !ok || id.Name != name {
+		return false
+	}
+	_, ok = info.ObjectOf(id).(*types.Builtin)
+	return ok
+}
+
+// isErrorType reports whether x type is the builtin error interface.
+func isErrorType(info *types.Info, x ast.Expr) bool {
+	return types.Identical(info.TypeOf(x), types.Universe.Lookup("error").Type())
+}
+
+// isErrCheck matches `err != nil` expression, where err can be any error expression.
+func isErrCheck(info *types.Info, cond ast.Expr) bool {
+	cmp := astcast.ToBinaryExpr(cond)
+	return cmp.Op == token.NEQ && astcast.ToIdent(cmp.Y).Name == "nil" && isErrorType(info, cmp.X)
+}
+
+// underlyingOf returns the underlying type of the x expression.
+// Returns nil if x type is unknown, like for the unresolved imports.
+func underlyingOf(info *types.Info, x ast.Expr) types.Type {
+	typ := info.TypeOf(x)
+	if typ == nil {
+		return nil
+	}
+	return typ.Underlying()
+}
+
+// isTestingPtr reports whether typ is a pointer to the testing package type,
+// like *testing.T for the "T" name.
+func isTestingPtr(typ types.Type, name string) bool {
+	ptr, ok := typ.(*types.Pointer)
+	if !ok {
+		return false
+	}
+	named, ok := ptr.Elem().(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return false
+	}
+	return named.Obj().Pkg().Path() == "testing" && named.Obj().Name() == name
+}
+
+// formatVerbs returns the verbs of the fmt format string, one per argument.
+// Returns nil if the format uses explicit argument indexes or
+// star width and precision, since they don't map to the arguments 1:1.
+func formatVerbs(format string) []byte {
+	directives := formatDirectives(format)
+	if directives == nil {
+		return nil
+	}
+	verbs := make([]byte, len(directives))
+	for i, d := range directives {
+		verbs[i] = d[len(d)-1]
+	}
+	return verbs
+}
+
+// formatDirectives is like formatVerbs, but returns the verbs along
+// with their flags, width and precision, like "+v" for `%+v`.
+func formatDirectives(format string) []string {
+	directives := []string{}
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+		i++
+		start := i
+		for i < len(format) && strings.IndexByte("+-# 0123456789.", format[i]) != -1 {
+			i++
+		}
+		if i == len(format) {
+			return nil
+		}
+		switch ch := format[i]; ch {
+		case '%':
+			// Literal percent sign, doesn't consume an argument.
+		case '*', '[':
+			return nil
+		default:
+			directives = append(directives, format[start:i+1])
+		}
+	}
+	return directives
+}
+
+// firstWord returns the first word of the camelCase or snake_case name,
+// like "no" for noCache, "Not" for NotFound and "no" for no_cache.
+func firstWord(name string) string {
+	for i := 1; i < len(name); i++ {
+		if name[i] == '_' || ('A' <= name[i] && name[i] <= 'Z') {
+			return name[:i]
+		}
+	}
+	return name
+}
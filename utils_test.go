@@ -0,0 +1,57 @@
+package main
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"testing"
+)
+
+func TestNormalizeLit(t *testing.T) {
+	tests := []struct {
+		kind  token.Token
+		value string
+		want  string
+	}{
+		{token.INT, "255", "255"},
+		{token.INT, "0xff", "255"},
+		{token.INT, "0XFF", "255"},
+		{token.INT, "0b1111_1111", "255"},
+		{token.INT, "0377", "255"},
+		{token.INT, "0o377", "255"},
+		{token.INT, "0xFFFFFFFFFFFFFFFFFF", "4722366482869645213695"},
+		{token.INT, "0xfg", ""},
+		{token.FLOAT, "1.", "1"},
+		{token.FLOAT, "1.0", "1"},
+		{token.FLOAT, ".5", "0.5"},
+		{token.FLOAT, "0.50", "0.5"},
+		{token.FLOAT, "5e-1", "0.5"},
+		{token.FLOAT, "0x1p-1", "0.5"},
+		{token.FLOAT, "1_000.5", "1000.5"},
+		{token.FLOAT, "1e5.0", ""},
+		{token.STRING, `"1"`, ""},
+	}
+	for _, test := range tests {
+		have := normalizeLit(test.kind, test.value)
+		if have != test.want {
+			t.Errorf("normalizeLit(%s, %s): have %q, want %q", test.kind, test.value, have, test.want)
+		}
+	}
+}
+
+func TestUnderlyingOf(t *testing.T) {
+	typed := ast.NewIdent("s")
+	untyped := ast.NewIdent("x")
+	sliceType := types.NewSlice(types.Typ[types.Int])
+	info := &types.Info{
+		Types: map[ast.Expr]types.TypeAndValue{
+			typed: {Type: types.NewNamed(types.NewTypeName(0, nil, "ints", nil), sliceType, nil)},
+		},
+	}
+	if typ := underlyingOf(info, typed); typ != sliceType {
+		t.Errorf("typed: have %v, want %v", typ, sliceType)
+	}
+	if typ := underlyingOf(info, untyped); typ != nil {
+		t.Errorf("untyped: have %v, want nil", typ)
+	}
+}
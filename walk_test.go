@@ -0,0 +1,105 @@
+package main
+
+import (
+	"go/ast"
+	"go/token"
+	"testing"
+
+	"github.com/go-toolsmith/astinfo"
+	"golang.org/x/tools/go/packages"
+)
+
+// collectFileCandidatesNaive is a reference implementation of the
+// collectFileCandidates that traverses a file once per checker.
+func (ctxt *context) collectFileCandidatesNaive(f *ast.File) {
+	ctxt.astinfo = astinfo.Info{
+		Parents: make(map[ast.Node]ast.Node),
+	}
+	ctxt.astinfo.Origin = f
+	ctxt.astinfo.Resolve()
+
+	for _, c := range ctxt.checkers {
+		for _, decl := range f.Decls {
+			ast.Inspect(decl, c.Visit)
+		}
+	}
+}
+
+func loadWalkTestPackage(tb testing.TB, ctxt *context) *packages.Package {
+	ctxt.fset = token.NewFileSet()
+	conf := &packages.Config{
+		Mode: packages.LoadSyntax,
+		Fset: ctxt.fset,
+	}
+	pkgs, err := packages.Load(conf, "go/types")
+	if err != nil {
+		tb.Fatalf("load: %v", err)
+	}
+	if len(pkgs) != 1 || len(pkgs[0].Errors) != 0 {
+		tb.Fatalf("load: unexpected result: %v", pkgs)
+	}
+	return pkgs[0]
+}
+
+func newWalkTestContext(tb testing.TB) (*context, *packages.Package) {
+	ctxt := &context{}
+	ctxt.flags.pedantic = true
+	if err := ctxt.initCheckers(); err != nil {
+		tb.Fatalf("init checkers: %v", err)
+	}
+	pkg := loadWalkTestPackage(tb, ctxt)
+	ctxt.info = pkg.TypesInfo
+	return ctxt, pkg
+}
+
+func TestSingleWalk(t *testing.T) {
+	ctxt, pkg := newWalkTestContext(t)
+
+	for _, f := range pkg.Syntax {
+		ctxt.collectFileCandidates(f)
+	}
+	have := ctxt.candidates
+	ctxt.candidates = nil
+	for _, f := range pkg.Syntax {
+		ctxt.collectFileCandidatesNaive(f)
+	}
+	want := ctxt.candidates
+
+	if len(have) != len(want) {
+		t.Fatalf("candidates count mismatch: have %d, want %d", len(have), len(want))
+	}
+	seen := make(map[candidate]int)
+	for _, c := range want {
+		seen[c]++
+	}
+	for _, c := range have {
+		seen[c]--
+	}
+	for c, n := range seen {
+		if n != 0 {
+			t.Errorf("candidate %+v: count mismatch", c)
+		}
+	}
+}
+
+func BenchmarkCollectFileCandidates(b *testing.B) {
+	ctxt, pkg := newWalkTestContext(b)
+
+	b.Run("single", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			ctxt.candidates = ctxt.candidates[:0]
+			for _, f := range pkg.Syntax {
+				ctxt.collectFileCandidates(f)
+			}
+		}
+	})
+
+	b.Run("naive", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			ctxt.candidates = ctxt.candidates[:0]
+			for _, f := range pkg.Syntax {
+				ctxt.collectFileCandidatesNaive(f)
+			}
+		}
+	})
+}
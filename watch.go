@@ -0,0 +1,175 @@
+package main
+
+import (
+	"fmt"
+	"go/build"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// watchInterval is a -watch file system polling interval.
+const watchInterval = time.Second
+
+// fileStamp describes the watched file version.
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// watch runs the analysis steps, waits for the target files
+// to change and runs them again, see -watch.
+//
+// Every run re-infers the conventions from scratch, using a new context
+// that shares only the flags, config and overlay with ctxt.
+// Errors of the single run are printed and don't stop the watching.
+// The -out file is rewritten by every run, see watchRun.
+//
+// Changes are detected by polling the targets every watchInterval:
+// directories are re-scanned, so new files and packages are noticed too.
+// Only returns if there is nothing to watch.
+func (ctxt *context) watch() error {
+	// The file opened by main is re-opened by every run.
+	if err := ctxt.closeOutput(); err != nil {
+		return err
+	}
+	for {
+		ctxt.watchRun()
+
+		stamps := ctxt.watchedFiles()
+		if len(stamps) == 0 {
+			return fmt.Errorf("no target files found")
+		}
+		for {
+			time.Sleep(watchInterval)
+			update := ctxt.watchedFiles()
+			if n := changedFiles(stamps, update); n != 0 {
+				ctxt.infoPrintf("watch: %d files changed", n)
+				break
+			}
+		}
+		ctxt.logger.Printf("watch: checking again")
+	}
+}
+
+// watchRun runs the analysis steps in a fresh context.
+// The -out file, if it's set, is truncated before the run
+// and closed after it, so it holds only the last run warnings.
+func (ctxt *context) watchRun() {
+	run := ctxt.newRun()
+	steps := append([]runStep{{"open output", run.openOutput}}, run.analysisSteps()...)
+	for _, step := range steps {
+		if err := step.fn(); err != nil {
+			ctxt.logger.Printf("%s: %v", step.name, err)
+			break
+		}
+	}
+	if err := run.closeOutput(); err != nil {
+		ctxt.logger.Printf("close output: %v", err)
+	}
+}
+
+// newRun returns a fresh context for a single -watch analysis run.
+func (ctxt *context) newRun() *context {
+	run := &context{
+		logger:       ctxt.logger,
+		out:          ctxt.out,
+		config:       ctxt.config,
+		overlay:      ctxt.overlay,
+		changedLines: ctxt.changedLines,
+		started:      time.Now(),
+	}
+	run.flags = ctxt.flags
+	return run
+}
+
+// watchedFiles returns the current versions of the target directories
+// and their Go files, along with the -archive and -config files.
+//
+// Local targets are scanned like the go tool does it for `./...` patterns:
+// testdata, vendor and directories that start with "." or "_" are skipped.
+// Import path targets are located using the go/build.
+func (ctxt *context) watchedFiles() map[string]fileStamp {
+	stamps := make(map[string]fileStamp)
+	add := func(filename string, info os.FileInfo) {
+		stamps[filename] = fileStamp{modTime: info.ModTime(), size: info.Size()}
+	}
+	addDir := func(dir string) {
+		infos, err := ioutil.ReadDir(dir)
+		if err != nil {
+			return
+		}
+		for _, info := range infos {
+			if !info.IsDir() && strings.HasSuffix(info.Name(), ".go") {
+				add(filepath.Join(dir, info.Name()), info)
+			}
+		}
+	}
+
+	for _, target := range ctxt.flags.targets {
+		recursive := strings.HasSuffix(target, "/...")
+		path := strings.TrimSuffix(target, "/...")
+		if !build.IsLocalImport(path) && !filepath.IsAbs(path) {
+			pkg, err := build.Import(path, "", build.FindOnly)
+			if err != nil {
+				ctxt.debugPrintf("watch: skip %q: %v", target, err)
+				continue
+			}
+			path = pkg.Dir
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		add(path, info)
+		if !info.IsDir() {
+			continue
+		}
+		if !recursive {
+			addDir(path)
+			continue
+		}
+		filepath.Walk(path, func(dir string, info os.FileInfo, err error) error {
+			if err != nil || !info.IsDir() {
+				return nil
+			}
+			name := info.Name()
+			if dir != path && (name == "testdata" || name == "vendor" ||
+				strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")) {
+				return filepath.SkipDir
+			}
+			add(dir, info)
+			addDir(dir)
+			return nil
+		})
+	}
+
+	for _, filename := range []string{ctxt.flags.archive, ctxt.flags.config} {
+		if filename == "" {
+			continue
+		}
+		if info, err := os.Stat(filename); err == nil {
+			add(filename, info)
+		}
+	}
+	return stamps
+}
+
+// changedFiles returns the number of added, removed and modified files.
+func changedFiles(old, update map[string]fileStamp) int {
+	n := 0
+	for filename, stamp := range update {
+		prev, ok := old[filename]
+		if !ok || !prev.modTime.Equal(stamp.modTime) || prev.size != stamp.size {
+			n++
+		}
+	}
+	for filename := range old {
+		if _, ok := update[filename]; !ok {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWatchedFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "go-consistent")
+	if err != nil {
+		t.Fatalf("create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	writeFile := func(name, data string) {
+		filename := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+			t.Fatalf("create dir: %v", err)
+		}
+		if err := ioutil.WriteFile(filename, []byte(data), 0644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+	writeFile("a.go", "package a\n")
+	writeFile("b/b.go", "package b\n")
+	writeFile("testdata/c.go", "package c\n")
+	writeFile("README.md", "readme\n")
+
+	ctxt := newTestContext(t)
+	ctxt.flags.targets = []string{dir + "/..."}
+	stamps := ctxt.watchedFiles()
+	for _, name := range []string{"", "a.go", "b", "b/b.go"} {
+		if _, ok := stamps[filepath.Join(dir, filepath.FromSlash(name))]; !ok {
+			t.Errorf("%q is not watched", name)
+		}
+	}
+	if len(stamps) != 4 {
+		t.Errorf("unexpected watched files: %v", stamps)
+	}
+	if n := changedFiles(stamps, ctxt.watchedFiles()); n != 0 {
+		t.Errorf("unexpected changes before the update: %d", n)
+	}
+
+	writeFile("b/b.go", "package b\n\nfunc f() {}\n")
+	writeFile("d/d.go", "package d\n")
+	writeFile("testdata/c.go", "package c\n\nfunc f() {}\n")
+	update := ctxt.watchedFiles()
+	if _, ok := update[filepath.Join(dir, "d", "d.go")]; !ok {
+		t.Errorf("new file is not watched")
+	}
+	if _, ok := update[filepath.Join(dir, "testdata", "c.go")]; ok {
+		t.Errorf("testdata file is watched")
+	}
+	if changedFiles(stamps, update) == 0 {
+		t.Errorf("changes are not detected")
+	}
+}
+
+func TestChangedFiles(t *testing.T) {
+	now := time.Now()
+	old := map[string]fileStamp{
+		"a.go": {modTime: now, size: 10},
+		"b.go": {modTime: now, size: 10},
+		"c.go": {modTime: now, size: 10},
+		"d.go": {modTime: now, size: 10},
+	}
+	update := map[string]fileStamp{
+		"a.go": {modTime: now, size: 10},
+		"b.go": {modTime: now.Add(time.Second), size: 10},
+		"c.go": {modTime: now, size: 20},
+		"e.go": {modTime: now, size: 10},
+	}
+	// b.go and c.go are modified, d.go is removed and e.go is added.
+	if n := changedFiles(old, update); n != 4 {
+		t.Errorf("changed files: have %d, want 4", n)
+	}
+	if n := changedFiles(old, old); n != 0 {
+		t.Errorf("changed files: have %d, want 0", n)
+	}
+}
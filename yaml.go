@@ -0,0 +1,176 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// parseYAML parses the subset of YAML that is enough for the config files:
+// nested block mappings of the plain, single-quoted and double-quoted scalars,
+// like in
+//
+//	operations:
+//	  empty map:
+//	    scope: package
+//
+// Comments and empty lines are skipped, keys without values are nulls.
+// All scalars are strings, except for the `null` and `~` nulls.
+// Sequences, flow collections, anchors, tags and multi-line scalars
+// are reported as errors.
+func parseYAML(data []byte) (map[string]interface{}, error) {
+	type frame struct {
+		indent int
+		m      map[string]interface{}
+	}
+	root := make(map[string]interface{})
+	stack := []frame{{indent: 0, m: root}}
+
+	// pending is a key without a value, it's either a null
+	// or a mapping that starts on the next line.
+	var pending *frame
+	var pendingKey string
+
+	for i, line := range strings.Split(string(data), "\n") {
+		lineNum := i + 1
+		line = strings.TrimSuffix(line, "\r")
+		content := strings.TrimLeft(line, " ")
+		if content == "" || content[0] == '#' || content == "---" {
+			continue
+		}
+		if content[0] == '\t' {
+			return nil, fmt.Errorf("line %d: tabs can't be used for indentation", lineNum)
+		}
+		indent := len(line) - len(content)
+
+		if pending != nil {
+			if indent > pending.indent {
+				child := make(map[string]interface{})
+				pending.m[pendingKey] = child
+				stack = append(stack, frame{indent: indent, m: child})
+			} else {
+				pending.m[pendingKey] = nil
+			}
+			pending = nil
+		}
+		for len(stack) > 1 && stack[len(stack)-1].indent > indent {
+			stack = stack[:len(stack)-1]
+		}
+		top := stack[len(stack)-1]
+		if top.indent != indent {
+			return nil, fmt.Errorf("line %d: unexpected indentation", lineNum)
+		}
+
+		key, value, ok, err := parseYAMLEntry(content)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", lineNum, err)
+		}
+		if _, dup := top.m[key]; dup {
+			return nil, fmt.Errorf("line %d: duplicated %q key", lineNum, key)
+		}
+		if !ok {
+			pending = &frame{indent: indent, m: top.m}
+			pendingKey = key
+			continue
+		}
+		top.m[key] = value
+	}
+	if pending != nil {
+		pending.m[pendingKey] = nil
+	}
+	return root, nil
+}
+
+// parseYAMLEntry parses a `key: value` mapping entry.
+// ok is false if the value is on the next lines.
+// Value is either a string or nil for the nulls.
+func parseYAMLEntry(s string) (key string, value interface{}, ok bool, err error) {
+	if strings.HasPrefix(s, "- ") || s == "-" {
+		return "", nil, false, fmt.Errorf("sequences are not supported")
+	}
+	key, rest, _, err := parseYAMLScalar(s, true)
+	if err != nil {
+		return "", nil, false, err
+	}
+	if !strings.HasPrefix(rest, ":") {
+		return "", nil, false, fmt.Errorf("expected `key: value` mapping entry")
+	}
+	rest = rest[1:]
+	if rest != "" && rest[0] != ' ' {
+		return "", nil, false, fmt.Errorf("expected a space after the %q key", key)
+	}
+	rest = strings.TrimLeft(rest, " ")
+	if rest == "" || rest[0] == '#' {
+		return key, nil, false, nil
+	}
+	v, rest, quoted, err := parseYAMLScalar(rest, false)
+	if err != nil {
+		return "", nil, false, err
+	}
+	if rest != "" && !strings.HasPrefix(rest, " #") {
+		return "", nil, false, fmt.Errorf("unexpected %q after the %q value", rest, key)
+	}
+	if !quoted && (v == "null" || v == "~") {
+		return key, nil, true, nil
+	}
+	return key, v, true, nil
+}
+
+// parseYAMLScalar parses the scalar at the start of s and returns
+// the rest of s, quoted reports whether the scalar is quoted.
+// Plain keys end at the first `: ` or the trailing colon,
+// plain values end at the first ` #` comment.
+func parseYAMLScalar(s string, isKey bool) (scalar, rest string, quoted bool, err error) {
+	switch s[0] {
+	case '"':
+		end := 1
+		for ; end < len(s) && s[end] != '"'; end++ {
+			if s[end] == '\\' {
+				end++
+			}
+		}
+		if end >= len(s) {
+			return "", "", false, fmt.Errorf("unterminated double-quoted string")
+		}
+		scalar, err = strconv.Unquote(s[:end+1])
+		if err != nil {
+			return "", "", false, fmt.Errorf("%s: %v", s[:end+1], err)
+		}
+		return scalar, strings.TrimRight(s[end+1:], " "), true, nil
+	case '\'':
+		var buf bytes.Buffer
+		for i := 1; i < len(s); i++ {
+			if s[i] != '\'' {
+				buf.WriteByte(s[i])
+				continue
+			}
+			if i+1 < len(s) && s[i+1] == '\'' {
+				buf.WriteByte('\'')
+				i++
+				continue
+			}
+			return buf.String(), strings.TrimRight(s[i+1:], " "), true, nil
+		}
+		return "", "", false, fmt.Errorf("unterminated single-quoted string")
+	case '{', '[', '|', '>', '&', '*', '!', '%', '@', '`':
+		return "", "", false, fmt.Errorf("unsupported YAML syntax: %s", s)
+	}
+
+	if isKey {
+		end := strings.Index(s, ": ")
+		if end == -1 {
+			if !strings.HasSuffix(s, ":") {
+				return s, "", false, nil
+			}
+			end = len(s) - 1
+		}
+		return strings.TrimRight(s[:end], " "), s[end:], false, nil
+	}
+	scalar = s
+	rest = ""
+	if end := strings.Index(s, " #"); end != -1 {
+		scalar, rest = s[:end], s[end:]
+	}
+	return strings.TrimRight(scalar, " "), rest, false, nil
+}
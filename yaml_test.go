@@ -0,0 +1,67 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseYAML(t *testing.T) {
+	tests := []struct {
+		data string
+		want map[string]interface{}
+	}{
+		{"", map[string]interface{}{}},
+		{"a: 1\nb: x y # Comment.\n", map[string]interface{}{"a": "1", "b": "x y"}},
+		{"a:\n  empty map:\n    scope: package\nb:\n", map[string]interface{}{
+			"a": map[string]interface{}{
+				"empty map": map[string]interface{}{"scope": "package"},
+			},
+			"b": nil,
+		}},
+		{"---\n# Comment.\na:\n\n  b: ~\n  c: 'null'\nd: null\n", map[string]interface{}{
+			"a": map[string]interface{}{"b": nil, "c": "null"},
+			"d": nil,
+		}},
+		{`a: "x: {{.Operation}} # y"` + "\n" + `b: 'it''s'`, map[string]interface{}{
+			"a": "x: {{.Operation}} # y",
+			"b": "it's",
+		}},
+		{"\"a b\": \"\\t\"\r\n", map[string]interface{}{"a b": "\t"}},
+	}
+
+	for _, test := range tests {
+		have, err := parseYAML([]byte(test.data))
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", test.data, err)
+			continue
+		}
+		if !reflect.DeepEqual(have, test.want) {
+			t.Errorf("%q: result mismatch:\nhave: %#v\nwant: %#v", test.data, have, test.want)
+		}
+	}
+}
+
+func TestParseYAMLErrors(t *testing.T) {
+	tests := []string{
+		"a:\n\tb: 1\n",
+		"a:\n    b: 1\n  c: 1\n",
+		"a: 1\n  b: 1\n",
+		"a: 1\na: 2\n",
+		"- a\n",
+		"a: [1, 2]\n",
+		"a: {b: 1}\n",
+		"a: |\n  text\n",
+		"a: &x 1\n",
+		"a: \"x\n",
+		"a: 'x\n",
+		"a: \"x\" y\n",
+		"a:b\n",
+		"a\n",
+	}
+
+	for _, data := range tests {
+		if _, err := parseYAML([]byte(data)); err == nil {
+			t.Errorf("%q: expected an error", data)
+		}
+	}
+}